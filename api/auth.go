@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type contextKey string
+
+const userContextKey contextKey = "user"
+
+// Register godoc
+//	@Summary	Register user
+//	@Tags		auth
+//	@Accept		json
+//	@Produce	json
+//	@Param		data	body		RegisterRequest	true	"Request Body"
+//	@Success	201		{object}	SuccessReponse{data=AuthResponse}
+//	@Failure	400		{object}	Problem
+//	@Failure	409		{object}	Problem
+//	@Router		/auth/register [post]
+func (a *Application) Register(w http.ResponseWriter, r *http.Request) {
+	var payload RegisterRequest
+
+	err := utils.DecodeJSON(r, &payload)
+	if err != nil {
+		msg := "Please provide a valid JSON body"
+		renderProblem(w, r, http.StatusBadRequest, msg)
+		return
+	}
+
+	if err = payload.Validate(); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(payload.Password), bcrypt.DefaultCost)
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("Register: " + err.Error())
+		return
+	}
+
+	user, err := a.users.CreateUser(r.Context(), &database.User{
+		Name:         payload.Name,
+		Email:        payload.Email,
+		PasswordHash: string(hash),
+	})
+	if err != nil {
+		if errors.Is(err, database.ErrEmailTaken) {
+			renderProblem(w, r, http.StatusConflict, "Email is already registered")
+			return
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("Register: " + err.Error())
+		return
+	}
+
+	a.renderAuthResponse(w, r, http.StatusCreated, user)
+}
+
+// Login godoc
+//	@Summary	Login user
+//	@Tags		auth
+//	@Accept		json
+//	@Produce	json
+//	@Param		data	body		LoginRequest	true	"Request Body"
+//	@Success	200		{object}	SuccessReponse{data=AuthResponse}
+//	@Failure	400		{object}	Problem
+//	@Failure	401		{object}	Problem
+//	@Router		/auth/login [post]
+func (a *Application) Login(w http.ResponseWriter, r *http.Request) {
+	var payload LoginRequest
+
+	err := utils.DecodeJSON(r, &payload)
+	if err != nil {
+		msg := "Please provide a valid JSON body"
+		renderProblem(w, r, http.StatusBadRequest, msg)
+		return
+	}
+
+	if err = payload.Validate(); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	user, err := a.users.GetUserByEmail(r.Context(), payload.Email)
+	if err != nil {
+		if errors.Is(err, database.ErrUserNotFound) {
+			renderProblem(w, r, http.StatusUnauthorized, "Invalid email or password")
+			return
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("Login: " + err.Error())
+		return
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(payload.Password))
+	if err != nil {
+		renderProblem(w, r, http.StatusUnauthorized, "Invalid email or password")
+		return
+	}
+
+	a.renderAuthResponse(w, r, http.StatusOK, user)
+}
+
+func (a *Application) renderAuthResponse(w http.ResponseWriter, r *http.Request, statusCode int, user *database.User) {
+	token, expiresAt, err := a.tokens.Issue(user.ID)
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("Issue Token: " + err.Error())
+		return
+	}
+
+	data := AuthResponse{Token: token, ExpiresAt: expiresAt, User: *user}
+	utils.RenderResponse(w, statusCode, NewSuccessResponse(data, nil))
+}
+
+// Authenticate stores the caller identified by the bearer token in the request context.
+// Requests without an Authorization header pass through anonymously, while invalid tokens are rejected.
+func (a *Application) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, found := strings.CutPrefix(header, "Bearer ")
+		if !found || token == "" {
+			renderProblem(w, r, http.StatusUnauthorized, "Invalid or expired token")
+			return
+		}
+
+		userID, err := a.tokens.Verify(token)
+		if err != nil {
+			renderProblem(w, r, http.StatusUnauthorized, "Invalid or expired token")
+			return
+		}
+
+		user, err := a.users.GetUserByID(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, database.ErrUserNotFound) {
+				renderProblem(w, r, http.StatusUnauthorized, "Invalid or expired token")
+				return
+			}
+
+			renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+			a.logger.Error("Authenticate: " + err.Error())
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userContextKey, user)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireAuth rejects anonymous requests. It must be mounted after Authenticate.
+func RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := userFromContext(r.Context()); !ok {
+			renderProblem(w, r, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// userFromContext returns the user stored by Authenticate, if any
+func userFromContext(ctx context.Context) (*database.User, bool) {
+	user, ok := ctx.Value(userContextKey).(*database.User)
+	return user, ok
+}
+
+// RequirePermission rejects callers whose role lacks the given permission.
+// It must be mounted after Authenticate.
+func RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := userFromContext(r.Context())
+			if !ok {
+				renderProblem(w, r, http.StatusUnauthorized, "Authentication required")
+				return
+			}
+
+			if !user.HasPermission(permission) {
+				renderProblem(w, r, http.StatusForbidden, "You do not have permission to perform this action")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// canModifyArticle reports whether the user may perform an action on the article,
+// either because they own it and hold ownPerm or because they hold anyPerm
+func canModifyArticle(user *database.User, article *database.Article, ownPerm, anyPerm string) bool {
+	if user.HasPermission(anyPerm) {
+		return true
+	}
+
+	isOwner := article.AuthorID != nil && *article.AuthorID == user.ID
+	return isOwner && user.HasPermission(ownPerm)
+}
+
+// canViewArticle reports whether the article is visible to the user, who may be nil.
+// Only published articles are public, everything else is limited to those who can edit it.
+func canViewArticle(user *database.User, article *database.Article) bool {
+	if article.Status == database.ArticleStatusPublished {
+		return true
+	}
+
+	return user != nil && canModifyArticle(user, article, database.PermArticlesUpdate, database.PermArticlesUpdateAny)
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+)
+
+// CachePolicies are the Cache-Control headers sent with public reads, per group of routes.
+// An empty policy sends no Cache-Control header.
+type CachePolicies struct {
+	// Articles covers article listings, single articles and their comments
+	Articles string
+	Tags     string
+	Feeds    string
+	Sitemap  string
+}
+
+// cacheControl sets policy on GET and HEAD responses to anonymous requests. Authenticated
+// requests can see drafts, so their responses are kept out of shared caches.
+func cacheControl(policy string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Authorization")
+			if r.Header.Get("Authorization") != "" {
+				w.Header().Set("Cache-Control", "private, no-cache")
+			} else if policy != "" {
+				w.Header().Set("Cache-Control", policy)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheControl(t *testing.T) {
+	handler := cacheControl("public, max-age=60")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		cacheControl  string
+	}{
+		{"anonymous read", http.MethodGet, "", "public, max-age=60"},
+		{"authenticated read", http.MethodGet, "Bearer token", "private, no-cache"},
+		{"write", http.MethodPost, "", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, "/articles", nil)
+			if tc.authorization != "" {
+				r.Header.Set("Authorization", tc.authorization)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			require.Equal(t, tc.cacheControl, w.Header().Get("Cache-Control"))
+		})
+	}
+}
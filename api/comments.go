@@ -0,0 +1,279 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/utils"
+	"github.com/go-chi/chi/v5"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// GetComments godoc
+//	@Summary		List comments on an article
+//	@Description	Returns approved comments as threads flattened depth first, each reply follows its parent with depth set.
+//	@Description	Pagination applies to top level comments.
+//	@Tags			comments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int	true	"Article ID"
+//	@Param			page		query		int	false	"Page"
+//	@Param			per_page	query		int	false	"Comments per page"
+//	@Success		200			{object}	SuccessReponse{data=GetCommentsResponse,metadata=database.PaginationData}
+//	@Success		304
+//	@Failure		404	{object}	Problem
+//	@Router			/articles/{id}/comments [get]
+func (a *Application) GetComments(w http.ResponseWriter, r *http.Request) {
+	article, ok := a.loadVisibleArticle(w, r)
+	if !ok {
+		return
+	}
+
+	comments, paginationData, err := a.comments.GetComments(r.Context(), article.ID, parsePaging(r))
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetComments: " + err.Error())
+		return
+	}
+
+	data := GetCommentsResponse{Comments: comments}
+	utils.RenderConditionalResponse(w, r, NewSuccessResponse(data, paginationData), time.Time{})
+}
+
+// CreateComment godoc
+//	@Summary		Comment on an article
+//	@Description	Signed in users' comments are published immediately. Anonymous comments need a guest_name and wait for moderation.
+//	@Tags			comments
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"Article ID"
+//	@Param			data	body		CreateCommentRequest	true	"Request Body"
+//	@Success		201		{object}	SuccessReponse{data=CreateCommentResponse}
+//	@Failure		400		{object}	Problem
+//	@Failure		403		{object}	Problem
+//	@Failure		404		{object}	Problem
+//	@Router			/articles/{id}/comments [post]
+func (a *Application) CreateComment(w http.ResponseWriter, r *http.Request) {
+	article, ok := a.loadVisibleArticle(w, r)
+	if !ok {
+		return
+	}
+
+	user, isAuthenticated := userFromContext(r.Context())
+	if isAuthenticated && !user.HasPermission(database.PermCommentsCreate) {
+		renderProblem(w, r, http.StatusForbidden, "You do not have permission to perform this action")
+		return
+	}
+
+	var payload CreateCommentRequest
+	if err := utils.DecodeJSON(r, &payload); err != nil {
+		renderProblem(w, r, http.StatusBadRequest, "Please provide a valid JSON body")
+		return
+	}
+
+	if err := payload.Validate(isAuthenticated); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	comment := &database.Comment{
+		ArticleID: article.ID,
+		ParentID:  payload.ParentID,
+		Body:      payload.Body,
+		Status:    database.CommentStatusPending,
+	}
+
+	if isAuthenticated {
+		comment.UserID = &user.ID
+		comment.Status = database.CommentStatusApproved
+	} else {
+		comment.GuestName = &payload.GuestName
+	}
+
+	// replies can only be made to visible comments on the same article
+	if payload.ParentID != nil {
+		parent, err := a.comments.GetCommentByID(r.Context(), article.ID, *payload.ParentID)
+		if err != nil && !errors.Is(err, database.ErrCommentNotFound) {
+			renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+			a.logger.Error("CreateComment: " + err.Error())
+			return
+		}
+
+		if parent == nil || parent.Status != database.CommentStatusApproved || parent.Deleted {
+			renderValidationError(w, r, validation.Errors{"parent_id": errors.New("comment not found")})
+			return
+		}
+	}
+
+	comment, err := a.comments.CreateComment(r.Context(), comment)
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("CreateComment: " + err.Error())
+		return
+	}
+
+	data := CreateCommentResponse{Comment: *comment}
+	utils.RenderResponse(w, http.StatusCreated, NewSuccessResponse(data, nil))
+}
+
+// DeleteComment godoc
+//	@Summary		Delete comment
+//	@Description	Removes the comment's body and author. Replies stay in the thread under the deleted comment.
+//	@Tags			comments
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path	int	true	"Article ID"
+//	@Param			commentID	path	int	true	"Comment ID"
+//	@Success		204
+//	@Failure		401	{object}	Problem
+//	@Failure		403	{object}	Problem
+//	@Failure		404	{object}	Problem
+//	@Router			/articles/{id}/comments/{commentID} [delete]
+func (a *Application) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	article, ok := a.loadVisibleArticle(w, r)
+	if !ok {
+		return
+	}
+
+	commentID, err := strconv.Atoi(chi.URLParam(r, "commentID"))
+	if err != nil {
+		renderProblem(w, r, http.StatusNotFound, "Comment not found")
+		return
+	}
+
+	comment, err := a.comments.GetCommentByID(r.Context(), article.ID, commentID)
+	if err != nil {
+		if errors.Is(err, database.ErrCommentNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Comment not found")
+			return
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("DeleteComment: " + err.Error())
+		return
+	}
+
+	if comment.Deleted {
+		renderProblem(w, r, http.StatusNotFound, "Comment not found")
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	isOwner := comment.UserID != nil && *comment.UserID == user.ID
+	if !isOwner && !user.HasPermission(database.PermCommentsModerate) {
+		renderProblem(w, r, http.StatusForbidden, "You are not allowed to delete this comment")
+		return
+	}
+
+	if err = a.comments.DeleteComment(r.Context(), comment.ID); err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("DeleteComment: " + err.Error())
+		return
+	}
+
+	utils.RenderResponse(w, http.StatusNoContent, nil)
+}
+
+// GetModerationQueue godoc
+//	@Summary	List comments awaiting moderation
+//	@Tags		moderation
+//	@Accept		json
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		status		query		string	false	"Comment status"	Enums(pending, approved, rejected, spam)	default(pending)
+//	@Param		page		query		int		false	"Page"
+//	@Param		per_page	query		int		false	"Comments per page"
+//	@Success	200			{object}	SuccessReponse{data=GetCommentsResponse,metadata=database.PaginationData}
+//	@Failure	400			{object}	Problem
+//	@Failure	401			{object}	Problem
+//	@Failure	403			{object}	Problem
+//	@Router		/moderation/comments [get]
+func (a *Application) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = database.CommentStatusPending
+	}
+
+	if !isCommentStatus(status) {
+		renderProblem(w, r, http.StatusBadRequest, "status must be one of pending, approved, rejected or spam")
+		return
+	}
+
+	comments, paginationData, err := a.comments.GetCommentsByStatus(r.Context(), status, parsePaging(r))
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetModerationQueue: " + err.Error())
+		return
+	}
+
+	data := GetCommentsResponse{Comments: comments}
+	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, paginationData))
+}
+
+// ModerateComment godoc
+//	@Summary	Approve or reject a comment
+//	@Tags		moderation
+//	@Accept		json
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		commentID	path		int						true	"Comment ID"
+//	@Param		data		body		ModerateCommentRequest	true	"Request Body"
+//	@Success	200			{object}	SuccessReponse{data=CreateCommentResponse}
+//	@Failure	400			{object}	Problem
+//	@Failure	401			{object}	Problem
+//	@Failure	403			{object}	Problem
+//	@Failure	404			{object}	Problem
+//	@Failure	409			{object}	Problem
+//	@Router		/moderation/comments/{commentID} [patch]
+func (a *Application) ModerateComment(w http.ResponseWriter, r *http.Request) {
+	commentID, err := strconv.Atoi(chi.URLParam(r, "commentID"))
+	if err != nil {
+		renderProblem(w, r, http.StatusNotFound, "Comment not found")
+		return
+	}
+
+	var payload ModerateCommentRequest
+	if err = utils.DecodeJSON(r, &payload); err != nil {
+		renderProblem(w, r, http.StatusBadRequest, "Please provide a valid JSON body")
+		return
+	}
+
+	if err = payload.Validate(); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	comment, err := a.comments.UpdateCommentStatus(r.Context(), commentID, payload.Status, user.ID)
+	if err != nil {
+		if errors.Is(err, database.ErrCommentNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Comment not found")
+			return
+		}
+
+		if errors.Is(err, database.ErrCommentDeleted) {
+			renderProblem(w, r, http.StatusConflict, "Deleted comments can't be moderated")
+			return
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("ModerateComment: " + err.Error())
+		return
+	}
+
+	data := CreateCommentResponse{Comment: *comment}
+	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, nil))
+}
+
+func isCommentStatus(status string) bool {
+	switch status {
+	case database.CommentStatusPending, database.CommentStatusApproved, database.CommentStatusRejected, database.CommentStatusSpam:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/feed"
+	"github.com/ayo-awe/blogging_api/utils"
+)
+
+const FEED_SIZE = 20
+
+// GetRSSFeed godoc
+//	@Summary	RSS 2.0 feed of the latest articles
+//	@Tags		feeds
+//	@Produce	xml
+//	@Param		tags	query		[]string	false	"Only include articles with any of these tags"
+//	@Success	200		{string}	string		"RSS document"
+//	@Success	304
+//	@Router		/feed.rss [get]
+func (a *Application) GetRSSFeed(w http.ResponseWriter, r *http.Request) {
+	a.serveFeed(w, r, feed.RSSContentType, (*feed.Feed).RSS)
+}
+
+// GetAtomFeed godoc
+//	@Summary	Atom feed of the latest articles
+//	@Tags		feeds
+//	@Produce	xml
+//	@Param		tags	query		[]string	false	"Only include articles with any of these tags"
+//	@Success	200		{string}	string		"Atom document"
+//	@Success	304
+//	@Router		/feed.atom [get]
+func (a *Application) GetAtomFeed(w http.ResponseWriter, r *http.Request) {
+	a.serveFeed(w, r, feed.AtomContentType, (*feed.Feed).Atom)
+}
+
+// GetJSONFeed godoc
+//	@Summary	JSON Feed of the latest articles
+//	@Tags		feeds
+//	@Produce	json
+//	@Param		tags	query		[]string	false	"Only include articles with any of these tags"
+//	@Success	200		{string}	string		"JSON Feed document"
+//	@Success	304
+//	@Router		/feed.json [get]
+func (a *Application) GetJSONFeed(w http.ResponseWriter, r *http.Request) {
+	a.serveFeed(w, r, feed.JSONContentType, (*feed.Feed).JSON)
+}
+
+func (a *Application) serveFeed(w http.ResponseWriter, r *http.Request, contentType string, encode func(*feed.Feed) ([]byte, error)) {
+	tags := parseTags(r.URL.Query().Get("tags"))
+	filter := database.ArticleFilter{Tags: tags, Status: database.ArticleStatusPublished}
+
+	articles, _, err := a.repo.GetArticles(r.Context(), filter, database.Paging{Page: 1, PerPage: FEED_SIZE})
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("serveFeed: " + err.Error())
+		return
+	}
+
+	a.renderArticles(RENDER_HTML, articles)
+	f := a.buildFeed(r, tags, articles)
+
+	body, err := encode(f)
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("serveFeed: " + err.Error())
+		return
+	}
+
+	// the ETag covers articles dropping out of the feed, which can move Last-Modified back
+	var lastModified time.Time
+	if len(articles) > 0 {
+		lastModified = f.Updated
+	}
+
+	utils.RenderConditional(w, r, contentType, body, lastModified)
+}
+
+func (a *Application) buildFeed(r *http.Request, tags database.Tags, articles []database.Article) *feed.Feed {
+	f := &feed.Feed{
+		Title:       a.site.Title,
+		Description: "The latest articles on " + a.site.Title,
+		Link:        a.site.BaseURL,
+		FeedURL:     a.site.BaseURL + r.URL.RequestURI(),
+		Updated:     time.Unix(0, 0).UTC(),
+		Items:       make([]feed.Item, len(articles)),
+	}
+
+	if len(tags) > 0 {
+		f.Title += ": " + strings.Join(tags, ", ")
+		f.Description += " tagged " + strings.Join(tags, ", ")
+	}
+
+	for i, article := range articles {
+		link := a.site.articleURL(article.Slug)
+		item := feed.Item{
+			ID:        link,
+			Title:     article.Title,
+			Link:      link,
+			Tags:      article.Tags,
+			Published: article.PublishedAt,
+			Updated:   article.UpdatedAt,
+		}
+
+		if article.ContentHTML != nil {
+			item.Content = *article.ContentHTML
+		}
+
+		if article.AuthorName != nil {
+			item.Author = *article.AuthorName
+		}
+
+		if article.UpdatedAt.After(f.Updated) {
+			f.Updated = article.UpdatedAt
+		}
+
+		f.Items[i] = item
+	}
+
+	return f
+}
@@ -2,10 +2,14 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ayo-awe/blogging_api/database"
 	"github.com/ayo-awe/blogging_api/utils"
@@ -16,25 +20,135 @@ const (
 	DEFAULT_PAGE     = 1
 	DEFAULT_PER_PAGE = 20
 	MAX_PER_PAGE     = 100
+	MAX_QUERY_LENGTH = 200
 )
 
+// Site describes the public blog, it is used to build absolute links to articles and API resources
+type Site struct {
+	Title string
+	// BaseURL is where the blog is served, without a trailing slash. Articles are expected at /articles/{slug}.
+	BaseURL string
+	// APIURL is where the routes of BuildRoutes are served, without a trailing slash
+	APIURL string
+}
+
+func (s Site) articleURL(slug string) string {
+	return s.BaseURL + "/articles/" + url.PathEscape(slug)
+}
+
+// apiURL is the absolute URL of route, a path as declared in BuildRoutes
+func (s Site) apiURL(route string) string {
+	return s.APIURL + route
+}
+
 type Application struct {
-	logger *slog.Logger
-	repo   database.ArticleRepository
+	logger   *slog.Logger
+	site     Site
+	repo     database.ArticleRepository
+	users    database.UserRepository
+	comments database.CommentRepository
+	tags     database.TagRepository
+	media    database.MediaRepository
+	uploads  Uploads
+	cache    CachePolicies
+	limits   RateLimits
+	tokens   *TokenIssuer
 }
 
-func NewApplication(logger *slog.Logger, repo database.ArticleRepository) *Application {
-	return &Application{logger, repo}
+func NewApplication(logger *slog.Logger, site Site, repo database.ArticleRepository, users database.UserRepository, comments database.CommentRepository, tags database.TagRepository, media database.MediaRepository, uploads Uploads, cache CachePolicies, limits RateLimits, tokens *TokenIssuer) *Application {
+	return &Application{logger, site, repo, users, comments, tags, media, uploads, cache, limits, tokens}
 }
 
 func (a *Application) BuildRoutes() chi.Router {
 	router := chi.NewRouter()
+	router.Use(exposeRequestID, a.RateLimit)
+	router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		renderProblem(w, r, http.StatusNotFound, "No route matches "+r.URL.Path)
+	})
+	router.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		renderProblem(w, r, http.StatusMethodNotAllowed, r.Method+" is not allowed on "+r.URL.Path)
+	})
+
+	router.Route("/auth", func(r chi.Router) {
+		r.Post("/register", a.Register)
+		r.Post("/login", a.Login)
+	})
+
+	router.Group(func(r chi.Router) {
+		r.Use(cacheControl(a.cache.Feeds))
+		r.Get("/feed.rss", a.GetRSSFeed)
+		r.Get("/feed.atom", a.GetAtomFeed)
+		r.Get("/feed.json", a.GetJSONFeed)
+	})
+
+	router.Get("/highlight.css", a.GetHighlightCSS)
+
+	router.Group(func(r chi.Router) {
+		r.Use(cacheControl(a.cache.Sitemap))
+		r.Get("/sitemap.xml", a.GetSitemap)
+		r.Get("/sitemap-{page:[0-9]+}.xml", a.GetSitemapPage)
+	})
+
 	router.Route("/articles", func(r chi.Router) {
-		r.Post("/", a.CreateArticle)
+		r.Use(a.Authenticate, cacheControl(a.cache.Articles))
 		r.Get("/", a.GetArticles)
 		r.Get("/{id}", a.GetArticleByID)
-		r.Patch("/{id}", a.UpdateArticle)
-		r.Delete("/{id}", a.DeleteArticle)
+		r.Get("/by-slug/{slug}", a.GetArticleBySlug)
+		r.Get("/{id}/comments", a.GetComments)
+		r.Post("/{id}/comments", a.CreateComment)
+
+		r.Group(func(r chi.Router) {
+			r.Use(RequireAuth)
+			r.With(RequirePermission(database.PermArticlesCreate)).Post("/", a.CreateArticle)
+			r.Patch("/{id}", a.UpdateArticle)
+			r.Put("/{id}", a.ReplaceArticle)
+			r.Delete("/{id}", a.DeleteArticle)
+
+			r.Group(func(r chi.Router) {
+				r.Use(RequirePermission(database.PermArticlesPublish))
+				r.Post("/{id}/publish", a.PublishArticle)
+				r.Post("/{id}/unpublish", a.UnpublishArticle)
+				r.Post("/{id}/archive", a.ArchiveArticle)
+			})
+
+			r.Get("/{id}/revisions", a.GetArticleRevisions)
+			r.Get("/{id}/revisions/{rev}", a.GetArticleRevision)
+			r.Get("/{id}/revisions/{a}/diff/{b}", a.DiffArticleRevisions)
+			r.Post("/{id}/revisions/{rev}/restore", a.RestoreArticleRevision)
+
+			r.Delete("/{id}/comments/{commentID}", a.DeleteComment)
+		})
+	})
+
+	router.Route("/tags", func(r chi.Router) {
+		r.With(cacheControl(a.cache.Tags)).Get("/", a.GetTags)
+		r.With(cacheControl(a.cache.Tags)).Get("/{slug}", a.GetTagBySlug)
+
+		r.Group(func(r chi.Router) {
+			r.Use(a.Authenticate, RequirePermission(database.PermTagsManage))
+			r.Patch("/{slug}", a.UpdateTag)
+			r.Post("/{slug}/rename", a.RenameTag)
+			r.Post("/merge", a.MergeTags)
+		})
+	})
+
+	router.Route("/media", func(r chi.Router) {
+		r.Use(a.Authenticate)
+		r.Get("/{id}", a.GetMedia)
+		r.With(RequirePermission(database.PermMediaUpload)).Post("/", a.UploadMedia)
+	})
+
+	router.Route("/moderation", func(r chi.Router) {
+		r.Use(a.Authenticate, RequirePermission(database.PermCommentsModerate))
+		r.Get("/comments", a.GetModerationQueue)
+		r.Patch("/comments/{commentID}", a.ModerateComment)
+	})
+
+	router.Route("/users", func(r chi.Router) {
+		r.Use(a.Authenticate, RequirePermission(database.PermUsersManage))
+		r.Get("/", a.GetUsers)
+		r.Get("/{id}", a.GetUserByID)
+		r.Patch("/{id}/role", a.UpdateUserRole)
 	})
 
 	return router
@@ -45,9 +159,12 @@ func (a *Application) BuildRoutes() chi.Router {
 //	@Tags		articles
 //	@Accept		json
 //	@Produce	json
+//	@Security	BearerAuth
 //	@Param		data	body		CreateArticleRequest	true	"Request Body"
-//	@Success	200		{object}	SuccessReponse{data=CreateArticleResponse}
-//	@Failure	400		{object}	ErrorResponse
+//	@Success	201		{object}	SuccessReponse{data=CreateArticleResponse}
+//	@Failure	400		{object}	Problem
+//	@Failure	401		{object}	Problem
+//	@Failure	403		{object}	Problem
 //	@Router		/articles [post]
 func (a *Application) CreateArticle(w http.ResponseWriter, r *http.Request) {
 	var payload CreateArticleRequest
@@ -55,49 +172,204 @@ func (a *Application) CreateArticle(w http.ResponseWriter, r *http.Request) {
 	err := utils.DecodeJSON(r, &payload)
 	if err != nil {
 		msg := "Please provide a valid JSON body"
-		utils.RenderResponse(w, http.StatusBadRequest, NewErrResponse(msg))
+		renderProblem(w, r, http.StatusBadRequest, msg)
 		return
 	}
 
 	err = payload.Validate()
 	if err != nil {
-		msg := err.Error()
-		utils.RenderResponse(w, http.StatusBadRequest, NewErrResponse(msg))
+		renderValidationError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	if payload.PublishAt != nil && !user.HasPermission(database.PermArticlesPublish) {
+		renderProblem(w, r, http.StatusForbidden, "You are not allowed to schedule articles")
 		return
 	}
 
-	article, err := a.repo.CreateArticle(r.Context(), payload.toArticle())
+	article := payload.toArticle()
+	article.AuthorID = &user.ID
+
+	article, err = a.repo.CreateArticle(r.Context(), article)
 	if err != nil {
 		msg := "An unexpected error occured"
-		utils.RenderResponse(w, http.StatusInternalServerError, NewErrResponse(msg))
+		renderProblem(w, r, http.StatusInternalServerError, msg)
 		a.logger.Error(err.Error())
 		return
 	}
 
 	data := CreateArticleResponse{Article: *article}
+	w.Header().Set("ETag", articleETag(article))
 	utils.RenderResponse(w, http.StatusCreated, NewSuccessResponse(data, nil))
 }
 
 // GetArticles godoc
-//	@Summary	List article
-//	@Tags		articles
-//	@Accept		json
-//	@Produce	json
-//	@Param		tags	query		[]string	false	"Filter by tags"
-//	@Param		page	query		int			false	"Page"
-//	@Param		perPage	query		int			false	"Articles per page"
-//	@Success	200		{object}	SuccessReponse{data=GetArticlesResponse,metadata=database.PaginationData}
-//	@Router		/articles [get]
+//	@Summary		List article
+//	@Description	Supports page-number pagination (page, per_page) and cursor pagination (cursor, limit).
+//	@Description	In cursor mode the metadata holds next_cursor and prev_cursor instead of page counts.
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			q				query		string		false	"Full-text search over title and content, results are ranked by relevance"
+//	@Param			tags			query		[]string	false	"Filter by tags"
+//	@Param			tags_mode		query		string		false	"Match articles with any or all of tags"	Enums(any, all)	default(any)
+//	@Param			exclude_tags	query		[]string	false	"Leave out articles with any of these tags"
+//	@Param			render			query		string		false	"Include content rendered to sanitized HTML as content_html"	Enums(raw, html)					default(raw)
+//	@Param			status			query		string		false	"Filter by status, only published articles are public"			Enums(draft, published, archived)	default(published)
+//	@Param			author_id		query		int			false	"Filter by author"
+//	@Param			page			query		int			false	"Page"
+//	@Param			perPage			query		int			false	"Articles per page"
+//	@Param			cursor			query		string		false	"Opaque cursor from next_cursor or prev_cursor"
+//	@Param			limit			query		int			false	"Articles per page in cursor mode"
+//	@Success		200				{object}	SuccessReponse{data=GetArticlesResponse,metadata=database.PaginationData}
+//	@Success		304
+//	@Failure		400	{object}	Problem
+//	@Failure		401	{object}	Problem
+//	@Router			/articles [get]
 func (a *Application) GetArticles(w http.ResponseWriter, r *http.Request) {
-	// get rawTags  from query params
-	var tags database.Tags
+	filter := database.ArticleFilter{
+		Tags:        parseTags(r.URL.Query().Get("tags")),
+		TagsMode:    database.TagsModeAny,
+		ExcludeTags: parseTags(r.URL.Query().Get("exclude_tags")),
+		Status:      database.ArticleStatusPublished,
+	}
+
+	renderMode, err := parseRender(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	rawTagsMode := r.URL.Query().Get("tags_mode")
+	if rawTagsMode != "" {
+		if rawTagsMode != database.TagsModeAny && rawTagsMode != database.TagsModeAll {
+			renderProblem(w, r, http.StatusBadRequest, "tags_mode must be one of any or all")
+			return
+		}
+		filter.TagsMode = rawTagsMode
+	}
+
+	filter.Query = strings.TrimSpace(r.URL.Query().Get("q"))
+	if len(filter.Query) > MAX_QUERY_LENGTH {
+		renderProblem(w, r, http.StatusBadRequest, fmt.Sprintf("q must be at most %d characters", MAX_QUERY_LENGTH))
+		return
+	}
+
+	rawAuthorID := r.URL.Query().Get("author_id")
+	if rawAuthorID != "" {
+		authorID, err := strconv.Atoi(rawAuthorID)
+		if err != nil {
+			renderProblem(w, r, http.StatusBadRequest, "author_id must be an integer")
+			return
+		}
+		filter.AuthorID = &authorID
+	}
+
+	rawStatus := r.URL.Query().Get("status")
+	if rawStatus != "" && rawStatus != database.ArticleStatusPublished {
+		if rawStatus != database.ArticleStatusDraft && rawStatus != database.ArticleStatusArchived {
+			renderProblem(w, r, http.StatusBadRequest, "status must be one of draft, published or archived")
+			return
+		}
 
-	rawTags := r.URL.Query().Get("tags")
-	if rawTags != "" {
-		mapFn := func(ele string) string { return strings.ToLower(strings.TrimSpace(ele)) }
-		tags = utils.Map(strings.Split(rawTags, ","), mapFn)
+		// unpublished articles are only listed for their author, or for those allowed to edit any article
+		user, ok := userFromContext(r.Context())
+		if !ok {
+			renderProblem(w, r, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+
+		if !user.HasPermission(database.PermArticlesUpdateAny) {
+			filter.AuthorID = &user.ID
+		}
+		filter.Status = rawStatus
 	}
 
+	query := r.URL.Query()
+	if query.Has("cursor") || query.Has("limit") {
+		a.getArticlesByCursor(w, r, filter, renderMode)
+		return
+	}
+
+	pageable := parsePaging(r)
+
+	// get articles by filter
+	articles, paginationData, err := a.repo.GetArticles(r.Context(), filter, pageable)
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error(err.Error())
+		return
+	}
+
+	a.renderArticles(renderMode, articles)
+	data := GetArticlesResponse{Articles: articles}
+
+	// lists have no Last-Modified, an article dropping out of a page would not move it forward
+	utils.RenderConditionalResponse(w, r, NewSuccessResponse(data, paginationData), time.Time{})
+}
+
+func (a *Application) getArticlesByCursor(w http.ResponseWriter, r *http.Request, filter database.ArticleFilter, renderMode string) {
+	if filter.Query != "" {
+		renderProblem(w, r, http.StatusBadRequest, "cursor pagination cannot be combined with q, use page instead")
+		return
+	}
+
+	paging, err := parseCursorPaging(r)
+	if err != nil {
+		renderProblem(w, r, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+
+	articles, paginationData, err := a.repo.GetArticlesByCursor(r.Context(), filter, paging)
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetArticlesByCursor: " + err.Error())
+		return
+	}
+
+	a.renderArticles(renderMode, articles)
+	data := GetArticlesResponse{Articles: articles}
+	utils.RenderConditionalResponse(w, r, NewSuccessResponse(data, paginationData), time.Time{})
+}
+
+// parseTags splits a comma separated list of tags, normalising them the way they are stored
+func parseTags(rawTags string) database.Tags {
+	if rawTags == "" {
+		return nil
+	}
+
+	mapFn := func(ele string) string { return strings.ToLower(strings.TrimSpace(ele)) }
+	return utils.Map(strings.Split(rawTags, ","), mapFn)
+}
+
+// parseCursorPaging reads the cursor and limit query params, the limit falls back to defaults like per_page
+func parseCursorPaging(r *http.Request) (database.CursorPaging, error) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = DEFAULT_PER_PAGE
+	}
+
+	if limit > MAX_PER_PAGE || limit <= 0 {
+		limit = MAX_PER_PAGE
+	}
+
+	paging := database.CursorPaging{Limit: limit}
+
+	rawCursor := r.URL.Query().Get("cursor")
+	if rawCursor != "" {
+		cursor, err := database.DecodeCursor(rawCursor)
+		if err != nil {
+			return database.CursorPaging{}, err
+		}
+		paging.Cursor = cursor
+	}
+
+	return paging, nil
+}
+
+// parsePaging reads the page and per_page query params, falling back to defaults
+func parsePaging(r *http.Request) database.Paging {
 	rawPage := r.URL.Query().Get("page")
 	page, err := strconv.Atoi(rawPage)
 	if err != nil || page <= 0 {
@@ -114,21 +386,10 @@ func (a *Application) GetArticles(w http.ResponseWriter, r *http.Request) {
 		perPage = MAX_PER_PAGE
 	}
 
-	pageable := database.Paging{
+	return database.Paging{
 		Page:    page,
 		PerPage: perPage,
 	}
-
-	// get articles by tags
-	articles, paginationData, err := a.repo.GetArticles(r.Context(), database.ArticleFilter{Tags: tags}, pageable)
-	if err != nil {
-		utils.RenderResponse(w, http.StatusInternalServerError, NewErrResponse("An unexpected error occured"))
-		a.logger.Error(err.Error())
-		return
-	}
-
-	data := GetArticlesResponse{Articles: articles}
-	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, paginationData))
 }
 
 // GetArticleByID godoc
@@ -136,52 +397,133 @@ func (a *Application) GetArticles(w http.ResponseWriter, r *http.Request) {
 //	@Tags		articles
 //	@Accept		json
 //	@Produce	json
-//	@Param		id	path		int	true	"Article ID"
-//	@Success	200	{object}	SuccessReponse{data=GetArticleByIDResponse}
-//	@Failure	404	{object}	ErrorResponse
+//	@Param		id		path		int		true	"Article ID"
+//	@Param		render	query		string	false	"Include content rendered to sanitized HTML as content_html"	Enums(raw, html)	default(raw)
+//	@Success	200		{object}	SuccessReponse{data=GetArticleByIDResponse}
+//	@Success	304
+//	@Failure	400	{object}	Problem
+//	@Failure	404	{object}	Problem
 //	@Router		/articles/{id} [get]
 func (a *Application) GetArticleByID(w http.ResponseWriter, r *http.Request) {
+	renderMode, err := parseRender(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
 	rawID := chi.URLParam(r, "id")
 
 	id, err := strconv.Atoi(rawID)
 	if err != nil {
-		utils.RenderResponse(w, http.StatusNotFound, NewErrResponse("Article not found"))
+		renderProblem(w, r, http.StatusNotFound, "Article not found")
 		return
 	}
 
 	article, err := a.repo.GetArticleByID(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, database.ErrArticleNotFound) {
-			utils.RenderResponse(w, http.StatusNotFound, NewErrResponse("Article not found"))
+			renderProblem(w, r, http.StatusNotFound, "Article not found")
 			return
 		}
 
-		utils.RenderResponse(w, http.StatusInternalServerError, NewErrResponse("An unexpected error occured"))
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
 		a.logger.Error("GetArticleByID: " + err.Error())
 		return
 	}
 
-	data := GetArticleByIDResponse{Article: *article}
-	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, nil))
+	user, _ := userFromContext(r.Context())
+	if !canViewArticle(user, article) {
+		renderProblem(w, r, http.StatusNotFound, "Article not found")
+		return
+	}
+
+	a.renderArticle(renderMode, article)
+	data := GetArticleByIDResponse{Article: *article, Toc: tableOfContents(article)}
+	utils.RenderVersionedResponse(w, r, NewSuccessResponse(data, nil), articleVersion(article), time.Time{})
+}
+
+// GetArticleBySlug godoc
+//	@Summary		Get article by slug
+//	@Description	Former slugs of an article answer with a permanent redirect to its current slug.
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			slug	path		string	true	"Article slug"
+//	@Param			render	query		string	false	"Include content rendered to sanitized HTML as content_html"	Enums(raw, html)	default(raw)
+//	@Success		200		{object}	SuccessReponse{data=GetArticleByIDResponse}
+//	@Success		304
+//	@Success		301
+//	@Failure		404	{object}	Problem
+//	@Router			/articles/by-slug/{slug} [get]
+func (a *Application) GetArticleBySlug(w http.ResponseWriter, r *http.Request) {
+	renderMode, err := parseRender(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	articleSlug := chi.URLParam(r, "slug")
+
+	article, err := a.repo.GetArticleBySlug(r.Context(), articleSlug)
+	if err != nil {
+		if errors.Is(err, database.ErrArticleNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Article not found")
+			return
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetArticleBySlug: " + err.Error())
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	if !canViewArticle(user, article) {
+		renderProblem(w, r, http.StatusNotFound, "Article not found")
+		return
+	}
+
+	if article.Slug != articleSlug {
+		location := path.Join(path.Dir(r.URL.Path), url.PathEscape(article.Slug))
+		if r.URL.RawQuery != "" {
+			location += "?" + r.URL.RawQuery
+		}
+
+		http.Redirect(w, r, location, http.StatusMovedPermanently)
+		return
+	}
+
+	a.renderArticle(renderMode, article)
+	data := GetArticleByIDResponse{Article: *article, Toc: tableOfContents(article)}
+	utils.RenderVersionedResponse(w, r, NewSuccessResponse(data, nil), articleVersion(article), time.Time{})
 }
 
 // UpdateArticle godoc
-//	@Summary	Update article
-//	@Tags		articles
-//	@Accept		json
-//	@Produce	json
-//	@Param		id		path		int						true	"Article ID"
-//	@Param		data	body		UpdateArticleRequest	true	"Request Body"
-//	@Success	200		{object}	SuccessReponse{data=UpdateArticleResponse}
-//	@Failure	400		{object}	ErrorResponse
-//	@Failure	404		{object}	ErrorResponse
-//	@Router		/articles/{id} [patch]
+//	@Summary		Update article
+//	@Description	A JSON body changes the fields it sets to non-empty values. Send application/merge-patch+json (RFC 7396)
+//	@Description	or application/json-patch+json (RFC 6902) to clear tags or publish_at, or for finer edits.
+//	@Tags			articles
+//	@Accept			json,application/merge-patch+json,application/json-patch+json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int						true	"Article ID"
+//	@Param			If-Match	header		string					false	"ETag of the version being updated, required unless version is sent"
+//	@Param			data		body		UpdateArticleRequest	true	"Request Body"
+//	@Success		200			{object}	SuccessReponse{data=UpdateArticleResponse}
+//	@Failure		400			{object}	Problem
+//	@Failure		401			{object}	Problem
+//	@Failure		403			{object}	Problem
+//	@Failure		404			{object}	Problem
+//	@Failure		409			{object}	Problem
+//	@Failure		412			{object}	Problem
+//	@Failure		415			{object}	Problem
+//	@Failure		428			{object}	Problem
+//	@Router			/articles/{id} [patch]
 func (a *Application) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 	rawID := chi.URLParam(r, "id")
 
 	id, err := strconv.Atoi(rawID)
 	if err != nil {
-		utils.RenderResponse(w, http.StatusNotFound, NewErrResponse("Article Not Found"))
+		renderProblem(w, r, http.StatusNotFound, "Article Not Found")
 		return
 	}
 
@@ -189,52 +531,62 @@ func (a *Application) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 	article, err := a.repo.GetArticleByID(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, database.ErrArticleNotFound) {
-			utils.RenderResponse(w, http.StatusNotFound, NewErrResponse("Article Not Found"))
+			renderProblem(w, r, http.StatusNotFound, "Article Not Found")
 			return
 		}
 		a.logger.Error("Update Article: " + err.Error())
-		utils.RenderResponse(w, http.StatusInternalServerError, NewErrResponse("An unexpected error occured"))
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
 		return
 	}
 
-	// parse request body
-	var payload UpdateArticleRequest
-	err = utils.DecodeJSON(r, &payload)
-	if err != nil {
-		a.logger.Error("Update Article: " + err.Error())
-		utils.RenderResponse(w, http.StatusInternalServerError, NewErrResponse("Failed to decode JSON request body"))
+	user, _ := userFromContext(r.Context())
+	if !canModifyArticle(user, article, database.PermArticlesUpdate, database.PermArticlesUpdateAny) {
+		renderProblem(w, r, http.StatusForbidden, "You are not allowed to modify this article")
 		return
 	}
 
-	// validate request body
-	if err = payload.Validate(); err != nil {
-		utils.RenderResponse(w, http.StatusBadRequest, NewErrResponse(err.Error()))
-		return
-	}
+	var doc ArticleDocument
+	var version *int
 
-	// update article with changes from request body
-	if payload.Title != "" {
-		article.Title = payload.Title
-	}
+	switch mediaType := patchMediaType(r); mediaType {
+	case "application/json":
+		// parse request body
+		var payload UpdateArticleRequest
+		err = utils.DecodeJSON(r, &payload)
+		if err != nil {
+			renderProblem(w, r, http.StatusBadRequest, "Failed to decode JSON request body")
+			return
+		}
 
-	if payload.Content != "" {
-		article.Content = payload.Content
-	}
+		// validate request body
+		if err = payload.Validate(); err != nil {
+			renderValidationError(w, r, err)
+			return
+		}
 
-	if len(payload.Tags) > 0 {
-		article.Tags = payload.Tags
-	}
+		// empty fields are left unchanged
+		doc = payload.apply(newArticleDocument(article))
+		version = payload.Version
 
-	// save updates in the databse
-	updatedArticle, err := a.repo.UpdateArticle(r.Context(), article)
-	if err != nil {
-		a.logger.Error("Update Article: " + err.Error())
-		utils.RenderResponse(w, http.StatusInternalServerError, NewErrResponse("Failed to decode JSON request body"))
+	case MERGE_PATCH_CONTENT_TYPE, JSON_PATCH_CONTENT_TYPE:
+		doc, version, err = patchArticle(r, mediaType, article)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, errPatchConflict) {
+				status = http.StatusConflict
+			}
+
+			renderProblem(w, r, status, "Patch could not be applied: "+err.Error())
+			return
+		}
+
+	default:
+		w.Header().Set("Accept-Patch", ACCEPT_PATCH)
+		renderProblem(w, r, http.StatusUnsupportedMediaType, "Content-Type must be one of "+ACCEPT_PATCH)
 		return
 	}
 
-	data := UpdateArticleResponse{Article: *updatedArticle}
-	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, nil))
+	a.saveArticle(w, r, article, doc, version)
 }
 
 // DeleteArticle godoc
@@ -242,9 +594,17 @@ func (a *Application) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 //	@Tags		articles
 //	@Accept		json
 //	@Produce	json
-//	@Param		id	path	int	true	"Article ID"
+//	@Security	BearerAuth
+//	@Param		id			path	int		true	"Article ID"
+//	@Param		If-Match	header	string	false	"ETag of the version being deleted, required unless version is sent"
+//	@Param		version		query	int		false	"Version being deleted, required unless If-Match is sent"
 //	@Success	204
-//	@Failure	404	{object}	ErrorResponse
+//	@Failure	400	{object}	Problem
+//	@Failure	401	{object}	Problem
+//	@Failure	403	{object}	Problem
+//	@Failure	404	{object}	Problem
+//	@Failure	412	{object}	Problem
+//	@Failure	428	{object}	Problem
 //	@Router		/articles/{id} [delete]
 func (a *Application) DeleteArticle(w http.ResponseWriter, r *http.Request) {
 	// get article id
@@ -252,28 +612,75 @@ func (a *Application) DeleteArticle(w http.ResponseWriter, r *http.Request) {
 
 	id, err := strconv.Atoi(rawID)
 	if err != nil {
-		utils.RenderResponse(w, http.StatusNotFound, NewErrResponse("Article Not Found"))
+		renderProblem(w, r, http.StatusNotFound, "Article Not Found")
 		return
 	}
 
-	_, err = a.repo.GetArticleByID(r.Context(), id)
+	article, err := a.repo.GetArticleByID(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, database.ErrArticleNotFound) {
-			utils.RenderResponse(w, http.StatusNotFound, NewErrResponse("Article Not Found"))
+			renderProblem(w, r, http.StatusNotFound, "Article Not Found")
 			return
 		}
 		a.logger.Error("Delete Article" + err.Error())
-		utils.RenderResponse(w, http.StatusInternalServerError, NewErrResponse("An unexpected error occured"))
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	if !canModifyArticle(user, article, database.PermArticlesDelete, database.PermArticlesDeleteAny) {
+		renderProblem(w, r, http.StatusForbidden, "You are not allowed to delete this article")
+		return
+	}
+
+	version, ok := queryVersion(w, r)
+	if !ok {
 		return
 	}
 
-	err = a.repo.DeleteArticle(r.Context(), id)
+	if !checkArticleVersion(w, r, article, version) {
+		return
+	}
+
+	err = a.repo.DeleteArticle(r.Context(), id, article.Version)
 	if err != nil {
+		if errors.Is(err, database.ErrArticleVersionConflict) {
+			renderVersionConflict(w, r)
+			return
+		}
 
 		a.logger.Error("Delete Article" + err.Error())
-		utils.RenderResponse(w, http.StatusInternalServerError, NewErrResponse("An unexpected error occured"))
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
 		return
 	}
 
 	utils.RenderResponse(w, http.StatusNoContent, nil)
 }
+
+// loadVisibleArticle fetches the article in the id URL param, hiding it unless the caller may view it
+func (a *Application) loadVisibleArticle(w http.ResponseWriter, r *http.Request) (*database.Article, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		renderProblem(w, r, http.StatusNotFound, "Article Not Found")
+		return nil, false
+	}
+
+	article, err := a.repo.GetArticleByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, database.ErrArticleNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Article Not Found")
+			return nil, false
+		}
+		a.logger.Error("Load Article: " + err.Error())
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		return nil, false
+	}
+
+	user, _ := userFromContext(r.Context())
+	if !canViewArticle(user, article) {
+		renderProblem(w, r, http.StatusNotFound, "Article Not Found")
+		return nil, false
+	}
+
+	return article, true
+}
@@ -0,0 +1,321 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/storage"
+	"github.com/ayo-awe/blogging_api/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// MAX_FILENAME_LENGTH caps the original filename kept for downloads
+const MAX_FILENAME_LENGTH = 255
+
+// mediaTypes are the content types accepted for uploads and the extension given to their keys.
+// SVG is left out because it can carry scripts.
+var mediaTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// Uploads configures where uploaded media is stored and how large it may be
+type Uploads struct {
+	Storage storage.Storage
+	// MaxSize is the largest accepted file in bytes
+	MaxSize int64
+}
+
+// UploadMedia godoc
+//	@Summary		Upload media
+//	@Description	Uploads an image or PDF. The type is detected from the file content, JPEG, PNG, GIF, WebP and PDF are accepted.
+//	@Description	Set article_id to attach the file to an article the caller can edit, it is then only served to those who can view the article.
+//	@Tags			media
+//	@Accept			mpfd
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			file		formData	file	true	"File to upload"
+//	@Param			article_id	formData	int		false	"Article the file belongs to"
+//	@Success		201			{object}	SuccessReponse{data=UploadMediaResponse}
+//	@Failure		400			{object}	Problem
+//	@Failure		401			{object}	Problem
+//	@Failure		403			{object}	Problem
+//	@Failure		413			{object}	Problem
+//	@Failure		415			{object}	Problem
+//	@Router			/media [post]
+func (a *Application) UploadMedia(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+
+	// leave room for the multipart boundaries and the other fields
+	r.Body = http.MaxBytesReader(w, r.Body, a.uploads.MaxSize+1<<20)
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			renderProblem(w, r, http.StatusRequestEntityTooLarge, tooLargeMessage(a.uploads.MaxSize))
+			return
+		}
+
+		renderProblem(w, r, http.StatusBadRequest, "Invalid multipart form")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		renderProblem(w, r, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	if header.Size > a.uploads.MaxSize {
+		renderProblem(w, r, http.StatusRequestEntityTooLarge, tooLargeMessage(a.uploads.MaxSize))
+		return
+	}
+
+	// the type declared by the client is ignored, it is detected from the first bytes instead
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		renderProblem(w, r, http.StatusBadRequest, "Invalid file")
+		return
+	}
+
+	contentType := http.DetectContentType(sniff[:n])
+	ext, ok := mediaTypes[contentType]
+	if !ok {
+		renderProblem(w, r, http.StatusUnsupportedMediaType, "Only JPEG, PNG, GIF, WebP and PDF files are accepted")
+		return
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("UploadMedia: " + err.Error())
+		return
+	}
+
+	media := &database.Media{
+		Filename:    mediaFilename(header.Filename, ext),
+		ContentType: contentType,
+		Size:        header.Size,
+		OwnerID:     &user.ID,
+	}
+
+	if rawArticleID := r.FormValue("article_id"); rawArticleID != "" {
+		articleID, err := strconv.Atoi(rawArticleID)
+		if err != nil {
+			renderProblem(w, r, http.StatusBadRequest, "article_id must be an integer")
+			return
+		}
+
+		article, err := a.repo.GetArticleByID(r.Context(), articleID)
+		if err != nil {
+			if errors.Is(err, database.ErrArticleNotFound) {
+				renderProblem(w, r, http.StatusBadRequest, "article_id does not match an article")
+				return
+			}
+
+			renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+			a.logger.Error("UploadMedia: " + err.Error())
+			return
+		}
+
+		if !canModifyArticle(user, article, database.PermArticlesUpdate, database.PermArticlesUpdateAny) {
+			renderProblem(w, r, http.StatusForbidden, "You do not have permission to perform this action")
+			return
+		}
+
+		media.ArticleID = &article.ID
+	}
+
+	key, err := mediaKey(ext)
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("UploadMedia: " + err.Error())
+		return
+	}
+	media.Key = key
+
+	if err := a.uploads.Storage.Put(r.Context(), key, file, header.Size, contentType); err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("UploadMedia: " + err.Error())
+		return
+	}
+
+	media, err = a.media.CreateMedia(r.Context(), media)
+	if err != nil {
+		// don't leave an object behind that nothing points to
+		if err := a.uploads.Storage.Delete(context.WithoutCancel(r.Context()), key); err != nil {
+			a.logger.Error("UploadMedia: " + err.Error())
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("UploadMedia: " + err.Error())
+		return
+	}
+
+	data := UploadMediaResponse{
+		Media: *media,
+		URL:   a.site.apiURL("/media/" + strconv.Itoa(media.ID)),
+	}
+	utils.RenderResponse(w, http.StatusCreated, NewSuccessResponse(data, nil))
+}
+
+// GetMedia godoc
+//	@Summary		Download media
+//	@Description	Serves an uploaded file. Files attached to an article are only served to those who can view the article,
+//	@Description	and once the article is deleted only to the uploader. Files never change, so files that don't belong to an article
+//	@Description	can be cached indefinitely, while article files are revalidated on every request.
+//	@Tags			media
+//	@Produce		image/jpeg,image/png,image/gif,image/webp,application/pdf
+//	@Param			id	path		int		true	"Media ID"
+//	@Success		200	{file}		file	"File content"
+//	@Success		304	{string}	string	"Not modified"
+//	@Failure		404	{object}	Problem
+//	@Router			/media/{id} [get]
+func (a *Application) GetMedia(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		renderProblem(w, r, http.StatusNotFound, "Media not found")
+		return
+	}
+
+	media, err := a.media.GetMediaByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, database.ErrMediaNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Media not found")
+			return
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetMedia: " + err.Error())
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+
+	cacheControl := "public, max-age=31536000, immutable"
+	switch {
+	case media.ArticleID != nil:
+		article, err := a.repo.GetArticleByID(r.Context(), *media.ArticleID)
+		if err != nil {
+			if errors.Is(err, database.ErrArticleNotFound) {
+				renderProblem(w, r, http.StatusNotFound, "Media not found")
+				return
+			}
+
+			renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+			a.logger.Error("GetMedia: " + err.Error())
+			return
+		}
+
+		if !canViewArticle(user, article) {
+			renderProblem(w, r, http.StatusNotFound, "Media not found")
+			return
+		}
+
+		// caches revalidate article files on every request, so unpublishing the article takes them down at once,
+		// and files of unpublished articles must not end up in shared caches at all
+		cacheControl = "public, no-cache"
+		if article.Status != database.ArticleStatusPublished {
+			cacheControl = "private, no-cache"
+		}
+
+	case media.Attached:
+		// the article was deleted, which must not make its files public
+		if !canAccessDetachedMedia(user, media) {
+			renderProblem(w, r, http.StatusNotFound, "Media not found")
+			return
+		}
+		cacheControl = "private, no-cache"
+	}
+
+	// the key is random and never reused, so it identifies the content
+	etag := `"` + path.Base(media.Key) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("Last-Modified", media.CreatedAt.UTC().Format(http.TimeFormat))
+
+	if utils.NotModified(r, etag, media.CreatedAt) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	body, err := a.uploads.Storage.Get(r.Context(), media.Key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Media not found")
+			return
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetMedia: " + err.Error())
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", media.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(media.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": media.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := io.Copy(w, body); err != nil {
+		a.logger.Error("GetMedia: " + err.Error())
+	}
+}
+
+// canAccessDetachedMedia reports whether the user, who may be nil, can get to a file whose article was deleted.
+// Only the uploader and those who can edit any article can.
+func canAccessDetachedMedia(user *database.User, media *database.Media) bool {
+	if user == nil {
+		return false
+	}
+
+	isOwner := media.OwnerID != nil && *media.OwnerID == user.ID
+	return isOwner || user.HasPermission(database.PermArticlesUpdateAny)
+}
+
+// mediaKey returns a random storage key, so keys can't be guessed and never collide
+func mediaKey(ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return "media/" + hex.EncodeToString(b) + ext, nil
+}
+
+// mediaFilename keeps the base name of the uploaded file for downloads
+func mediaFilename(name, ext string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" || name == "" {
+		name = "upload" + ext
+	}
+
+	if len(name) > MAX_FILENAME_LENGTH {
+		name = name[len(name)-MAX_FILENAME_LENGTH:]
+	}
+
+	return name
+}
+
+func tooLargeMessage(maxSize int64) string {
+	return fmt.Sprintf("Files must be at most %d bytes", maxSize)
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeArticles keeps articles in memory, methods the tests don't need are left to the nil interface
+type fakeArticles struct {
+	database.ArticleRepository
+	articles  map[int]*database.Article
+	revisions []*database.ArticleRevision
+	media     *fakeMedia
+}
+
+func (f *fakeArticles) GetArticleByID(ctx context.Context, ID int) (*database.Article, error) {
+	article, ok := f.articles[ID]
+	if !ok {
+		return nil, database.ErrArticleNotFound
+	}
+	return article, nil
+}
+
+func (f *fakeArticles) DeleteArticle(ctx context.Context, ID, version int) error {
+	delete(f.articles, ID)
+
+	// like the foreign key, which sets article_id to NULL
+	for _, media := range f.media.media {
+		if media.ArticleID != nil && *media.ArticleID == ID {
+			media.ArticleID = nil
+		}
+	}
+	return nil
+}
+
+type fakeMedia struct {
+	media map[int]*database.Media
+}
+
+func (f *fakeMedia) CreateMedia(ctx context.Context, media *database.Media) (*database.Media, error) {
+	created := *media
+	created.ID = len(f.media) + 1
+	created.Attached = media.ArticleID != nil
+	f.media[created.ID] = &created
+	return &created, nil
+}
+
+func (f *fakeMedia) GetMediaByID(ctx context.Context, ID int) (*database.Media, error) {
+	media, ok := f.media[ID]
+	if !ok {
+		return nil, database.ErrMediaNotFound
+	}
+
+	found := *media
+	return &found, nil
+}
+
+func TestMediaOfDeletedArticle(t *testing.T) {
+	files, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	author := &database.User{ID: 1, Permissions: []string{database.PermMediaUpload, database.PermArticlesUpdate}}
+	media := &fakeMedia{media: map[int]*database.Media{}}
+	articles := &fakeArticles{
+		articles: map[int]*database.Article{
+			7: {ID: 7, Title: "Draft", AuthorID: &author.ID, Status: database.ArticleStatusDraft},
+		},
+		media: media,
+	}
+
+	app := &Application{
+		logger:  slog.Default(),
+		site:    Site{BaseURL: "https://blog.example.com", APIURL: "https://api.example.com/api"},
+		repo:    articles,
+		media:   media,
+		uploads: Uploads{Storage: files, MaxSize: 1 << 20},
+	}
+
+	withUser := func(r *http.Request, user *database.User) *http.Request {
+		if user == nil {
+			return r
+		}
+		return r.WithContext(context.WithValue(r.Context(), userContextKey, user))
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	require.NoError(t, form.WriteField("article_id", "7"))
+	part, err := form.CreateFormFile("file", "diagram.png")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("\x89PNG\r\n\x1a\nnot really an image"))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	r := httptest.NewRequest(http.MethodPost, "/media", &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+	app.UploadMedia(w, withUser(r, author))
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Contains(t, w.Body.String(), `"url":"https://api.example.com/api/media/1"`)
+
+	require.NoError(t, articles.DeleteArticle(context.Background(), 7, 1))
+
+	get := func(user *database.User) *httptest.ResponseRecorder {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", strconv.Itoa(1))
+
+		r := httptest.NewRequest(http.MethodGet, "/media/1", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		w := httptest.NewRecorder()
+		app.GetMedia(w, withUser(r, user))
+		return w
+	}
+
+	t.Run("hidden from anonymous clients", func(t *testing.T) {
+		w := get(nil)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("hidden from other users", func(t *testing.T) {
+		w := get(&database.User{ID: 2, Permissions: []string{database.PermArticlesUpdate}})
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("served privately to the uploader", func(t *testing.T) {
+		w := get(author)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+	})
+
+	t.Run("not found when the article disappears between lookups", func(t *testing.T) {
+		missing := 99
+		media.media[1].ArticleID = &missing
+
+		w := get(author)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -2,8 +2,11 @@ package api
 
 import (
 	"strings"
+	"time"
 
 	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/diff"
+	"github.com/ayo-awe/blogging_api/render"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 )
@@ -14,9 +17,20 @@ type SuccessReponse struct {
 	Metadata interface{} `json:"metadata,omitempty" swaggerignore:"true"`
 }
 
-type ErrorResponse struct {
-	Status  string `json:"status" example:"error"`
-	Message string `json:"message" example:"Invalid Request Body"`
+// Problem describes an error as an RFC 7807 problem details object
+type Problem struct {
+	// Type identifies the kind of problem, it stays the same across releases
+	Type   string `json:"type" example:"urn:blogging-api:problem:validation-failed"`
+	Title  string `json:"title" example:"Bad Request"`
+	Status int    `json:"status" example:"400"`
+	Detail string `json:"detail" example:"One or more fields are invalid"`
+	// Instance is the path of the request that failed
+	Instance string `json:"instance" example:"/api/articles"`
+	// Code is the last part of Type
+	Code      string `json:"code" example:"validation-failed"`
+	RequestID string `json:"request_id,omitempty" example:"blog-host/Xyz12AbCde-000001"`
+	// Errors maps invalid fields, nested ones joined by dots such as tags.0, to what is wrong with them
+	Errors map[string]string `json:"errors,omitempty" example:"title:cannot be blank"`
 }
 
 type CreateArticleResponse struct {
@@ -25,6 +39,8 @@ type CreateArticleResponse struct {
 
 type GetArticleByIDResponse struct {
 	Article database.Article `json:"article"`
+	// Toc lists the headings of Markdown articles, nested by level, with their anchors in content_html
+	Toc []render.Heading `json:"toc"`
 }
 
 type UpdateArticleResponse struct {
@@ -35,6 +51,86 @@ type GetArticlesResponse struct {
 	Articles []database.Article `json:"articles"`
 }
 
+type AuthResponse struct {
+	Token     string        `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresAt time.Time     `json:"expires_at" example:"2024-06-24T22:21:19.00199+01:00"`
+	User      database.User `json:"user"`
+}
+
+type GetArticleRevisionsResponse struct {
+	Revisions []database.ArticleRevision `json:"revisions"`
+}
+
+type GetArticleRevisionResponse struct {
+	Revision database.ArticleRevision `json:"revision"`
+}
+
+type RevisionDiffResponse struct {
+	From    int         `json:"from" example:"2"`
+	To      int         `json:"to" example:"3"`
+	Title   TitleDiff   `json:"title"`
+	Tags    TagsDiff    `json:"tags"`
+	Content ContentDiff `json:"content"`
+}
+
+type TitleDiff struct {
+	Changed bool   `json:"changed" example:"true"`
+	From    string `json:"from" example:"I love Golang"`
+	To      string `json:"to" example:"I really love Golang"`
+}
+
+type TagsDiff struct {
+	Added   database.Tags `json:"added" example:"go"`
+	Removed database.Tags `json:"removed" example:"tech"`
+}
+
+type ContentDiff struct {
+	// Unified is a line level diff in unified format, empty when the content is unchanged
+	Unified string `json:"unified" example:"--- revision 2\n+++ revision 3\n@@ -1 +1 @@\n-lorem\n+ipsum\n"`
+	// Words is only included when requested with words=true
+	Words []diff.Edit `json:"words,omitempty"`
+}
+
+type GetCommentsResponse struct {
+	Comments []database.Comment `json:"comments"`
+}
+
+type GetTagsResponse struct {
+	Tags []database.Tag `json:"tags"`
+}
+
+type GetTagResponse struct {
+	Tag      database.Tag       `json:"tag"`
+	Articles []database.Article `json:"articles"`
+}
+
+type UpdateTagResponse struct {
+	Tag database.Tag `json:"tag"`
+}
+
+type MergeTagsResponse struct {
+	Tag             database.Tag `json:"tag"`
+	ArticlesUpdated int          `json:"articles_updated" example:"14"`
+}
+
+type CreateCommentResponse struct {
+	Comment database.Comment `json:"comment"`
+}
+
+type UploadMediaResponse struct {
+	Media database.Media `json:"media"`
+	// URL is where the file is served
+	URL string `json:"url" example:"https://blog.example.com/api/media/1"`
+}
+
+type GetUsersResponse struct {
+	Users []database.User `json:"users"`
+}
+
+type GetUserByIDResponse struct {
+	User database.User `json:"user"`
+}
+
 func NewSuccessResponse(data interface{}, metadata interface{}) *SuccessReponse {
 	return &SuccessReponse{
 		Status:   "success",
@@ -43,24 +139,21 @@ func NewSuccessResponse(data interface{}, metadata interface{}) *SuccessReponse
 	}
 }
 
-func NewErrResponse(msg string) *ErrorResponse {
-	return &ErrorResponse{
-		Status:  "error",
-		Message: msg,
-	}
-}
-
 type CreateArticleRequest struct {
-	Title   string        `json:"title" example:"I love Golang"`
-	Content string        `json:"content" example:"lorem ipsum lorem ipsum lorem ipsum"`
-	Tags    database.Tags `json:"tags" example:"golang,tech"`
+	Title         string        `json:"title" example:"I love Golang"`
+	Content       string        `json:"content" example:"lorem ipsum lorem ipsum lorem ipsum"`
+	ContentFormat string        `json:"content_format" example:"markdown"`
+	Tags          database.Tags `json:"tags" example:"golang,tech"`
+	PublishAt     *time.Time    `json:"publish_at" example:"2024-06-25T09:00:00Z"`
 }
 
 func (c *CreateArticleRequest) toArticle() *database.Article {
 	return &database.Article{
-		Title:   c.Title,
-		Content: c.Content,
-		Tags:    c.Tags,
+		Title:         c.Title,
+		Content:       c.Content,
+		ContentFormat: c.ContentFormat,
+		Tags:          c.Tags,
+		PublishAt:     c.PublishAt,
 	}
 }
 
@@ -69,13 +162,16 @@ func (c *CreateArticleRequest) Validate() error {
 	return validation.ValidateStruct(c,
 		validation.Field(&c.Title, validation.Length(5, 255)),
 		validation.Field(&c.Content, validation.Length(5, 0)),
+		validation.Field(&c.ContentFormat, validation.In(database.ContentFormatMarkdown, database.ContentFormatPlain)),
 		validation.Field(&c.Tags, validation.Each(validation.Length(2, 0), is.LowerCase)),
+		validation.Field(&c.PublishAt, validation.Min(time.Now()).Error("must be in the future")),
 	)
 }
 
 func (c *CreateArticleRequest) clean() {
 	c.Title = strings.TrimSpace(c.Title)
 	c.Content = strings.TrimSpace(c.Content)
+	c.ContentFormat = strings.ToLower(strings.TrimSpace(c.ContentFormat))
 
 	for i, tag := range c.Tags {
 		trimmed := strings.TrimSpace(tag)
@@ -84,9 +180,13 @@ func (c *CreateArticleRequest) clean() {
 }
 
 type UpdateArticleRequest struct {
-	Title   string        `json:"title" example:"I love Golang"`
-	Content string        `json:"content" example:"lorem ipsum lorem ipsum lorem ipsum"`
-	Tags    database.Tags `json:"tags" example:"golang,tech"`
+	Title         string        `json:"title" example:"I love Golang"`
+	Content       string        `json:"content" example:"lorem ipsum lorem ipsum lorem ipsum"`
+	ContentFormat string        `json:"content_format" example:"markdown"`
+	Tags          database.Tags `json:"tags" example:"golang,tech"`
+	PublishAt     *time.Time    `json:"publish_at" example:"2024-06-25T09:00:00Z"`
+	// Version is the version of the article being updated, required unless it is named in If-Match
+	Version *int `json:"version" example:"5"`
 }
 
 func (u *UpdateArticleRequest) Validate() error {
@@ -94,16 +194,192 @@ func (u *UpdateArticleRequest) Validate() error {
 	return validation.ValidateStruct(u,
 		validation.Field(&u.Title, validation.Length(5, 255)),
 		validation.Field(&u.Content, validation.Length(5, 0)),
+		validation.Field(&u.ContentFormat, validation.In(database.ContentFormatMarkdown, database.ContentFormatPlain)),
 		validation.Field(&u.Tags, validation.Each(validation.Length(2, 0), is.LowerCase)),
+		validation.Field(&u.PublishAt, validation.Min(time.Now()).Error("must be in the future")),
 	)
 }
 
+// apply overwrites doc with the fields set in the request
+func (u *UpdateArticleRequest) apply(doc ArticleDocument) ArticleDocument {
+	if u.Title != "" {
+		doc.Title = u.Title
+	}
+
+	if u.Content != "" {
+		doc.Content = u.Content
+	}
+
+	if u.ContentFormat != "" {
+		doc.ContentFormat = u.ContentFormat
+	}
+
+	if len(u.Tags) > 0 {
+		doc.Tags = u.Tags
+	}
+
+	if u.PublishAt != nil {
+		doc.PublishAt = u.PublishAt
+	}
+
+	return doc
+}
+
 func (u *UpdateArticleRequest) clean() {
 	u.Title = strings.TrimSpace(u.Title)
 	u.Content = strings.TrimSpace(u.Content)
+	u.ContentFormat = strings.ToLower(strings.TrimSpace(u.ContentFormat))
 
 	for i, tag := range u.Tags {
 		trimmed := strings.TrimSpace(tag)
 		u.Tags[i] = strings.ToLower(trimmed)
 	}
 }
+
+// ArticleDocument is the editable part of an article. JSON Merge Patch and JSON Patch requests
+// are applied to it, and PUT replaces it.
+type ArticleDocument struct {
+	Title         string        `json:"title" example:"I love Golang"`
+	Content       string        `json:"content" example:"lorem ipsum lorem ipsum lorem ipsum"`
+	ContentFormat string        `json:"content_format" example:"markdown"`
+	Tags          database.Tags `json:"tags" example:"golang,tech"`
+	PublishAt     *time.Time    `json:"publish_at" example:"2024-06-25T09:00:00Z"`
+}
+
+func newArticleDocument(article *database.Article) ArticleDocument {
+	tags := article.Tags
+	if tags == nil {
+		tags = database.Tags{}
+	}
+
+	return ArticleDocument{
+		Title:         article.Title,
+		Content:       article.Content,
+		ContentFormat: article.ContentFormat,
+		Tags:          tags,
+		PublishAt:     article.PublishAt,
+	}
+}
+
+type ReplaceArticleRequest struct {
+	ArticleDocument
+	// Version is the version of the article being replaced, required unless it is named in If-Match
+	Version *int `json:"version" example:"5"`
+}
+
+type RegisterRequest struct {
+	Name     string `json:"name" example:"Ayo Awe"`
+	Email    string `json:"email" example:"ayo@example.com"`
+	Password string `json:"password" example:"correct-horse-battery-staple"`
+}
+
+func (r *RegisterRequest) Validate() error {
+	r.clean()
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Name, validation.Required, validation.Length(2, 255)),
+		validation.Field(&r.Email, validation.Required, validation.Length(0, 255), is.EmailFormat),
+		validation.Field(&r.Password, validation.Required, validation.Length(8, 72)),
+	)
+}
+
+func (r *RegisterRequest) clean() {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Email = strings.ToLower(strings.TrimSpace(r.Email))
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" example:"ayo@example.com"`
+	Password string `json:"password" example:"correct-horse-battery-staple"`
+}
+
+func (l *LoginRequest) Validate() error {
+	l.clean()
+	return validation.ValidateStruct(l,
+		validation.Field(&l.Email, validation.Required),
+		validation.Field(&l.Password, validation.Required),
+	)
+}
+
+func (l *LoginRequest) clean() {
+	l.Email = strings.ToLower(strings.TrimSpace(l.Email))
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" example:"editor"`
+}
+
+func (u *UpdateUserRoleRequest) Validate() error {
+	u.Role = strings.ToLower(strings.TrimSpace(u.Role))
+	return validation.ValidateStruct(u,
+		validation.Field(&u.Role, validation.Required),
+	)
+}
+
+type CreateCommentRequest struct {
+	Body     string `json:"body" example:"Great article!"`
+	ParentID *int   `json:"parent_id" example:"1"`
+	// GuestName is required when commenting without signing in
+	GuestName string `json:"guest_name" example:"Jane"`
+}
+
+func (c *CreateCommentRequest) Validate(isAuthenticated bool) error {
+	c.Body = strings.TrimSpace(c.Body)
+	c.GuestName = strings.TrimSpace(c.GuestName)
+	return validation.ValidateStruct(c,
+		validation.Field(&c.Body, validation.Required, validation.Length(1, 5000)),
+		validation.Field(&c.GuestName, validation.When(!isAuthenticated, validation.Required), validation.Length(2, 100)),
+	)
+}
+
+type ModerateCommentRequest struct {
+	Status string `json:"status" example:"approved"`
+}
+
+func (m *ModerateCommentRequest) Validate() error {
+	m.Status = strings.ToLower(strings.TrimSpace(m.Status))
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Status, validation.Required, validation.In(
+			database.CommentStatusPending,
+			database.CommentStatusApproved,
+			database.CommentStatusRejected,
+			database.CommentStatusSpam,
+		)),
+	)
+}
+
+type UpdateTagRequest struct {
+	Description string `json:"description" example:"Machine learning, neural networks and everything in between"`
+}
+
+func (u *UpdateTagRequest) Validate() error {
+	u.Description = strings.TrimSpace(u.Description)
+	return validation.ValidateStruct(u,
+		validation.Field(&u.Description, validation.Length(0, 1000)),
+	)
+}
+
+type RenameTagRequest struct {
+	Name string `json:"name" example:"go"`
+}
+
+func (r *RenameTagRequest) Validate() error {
+	r.Name = strings.ToLower(strings.TrimSpace(r.Name))
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Name, validation.Required, validation.Length(2, 100)),
+	)
+}
+
+type MergeTagsRequest struct {
+	// Tags are the slugs of the tags to merge
+	Tags []string `json:"tags" example:"golang,go-lang"`
+	// Into is the name of the resulting tag, it can be one of the merged tags or a new one
+	Into string `json:"into" example:"go"`
+}
+
+func (m *MergeTagsRequest) Validate() error {
+	m.Into = strings.ToLower(strings.TrimSpace(m.Into))
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Tags, validation.Required, validation.Length(1, 50), validation.Each(validation.Required)),
+		validation.Field(&m.Into, validation.Required, validation.Length(2, 100)),
+	)
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/utils"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/go-chi/chi/v5"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	MERGE_PATCH_CONTENT_TYPE = "application/merge-patch+json"
+	JSON_PATCH_CONTENT_TYPE  = "application/json-patch+json"
+)
+
+// ACCEPT_PATCH lists the formats PATCH /articles/{id} understands
+var ACCEPT_PATCH = strings.Join([]string{"application/json", MERGE_PATCH_CONTENT_TYPE, JSON_PATCH_CONTENT_TYPE}, ", ")
+
+// errPatchConflict is returned for patches that are well formed but can't be applied to the article,
+// such as a failed test operation or a path that does not exist
+var errPatchConflict = errors.New("patch conflict")
+
+// ReplaceArticle godoc
+//	@Summary		Replace article
+//	@Description	Replaces the article's title, content, content format, tags and schedule. Tags and publish_at left out are cleared.
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int						true	"Article ID"
+//	@Param			If-Match	header		string					false	"ETag of the version being replaced, required unless version is sent"
+//	@Param			data		body		ReplaceArticleRequest	true	"Request Body"
+//	@Success		200			{object}	SuccessReponse{data=UpdateArticleResponse}
+//	@Failure		400			{object}	Problem
+//	@Failure		401			{object}	Problem
+//	@Failure		403			{object}	Problem
+//	@Failure		404			{object}	Problem
+//	@Failure		409			{object}	Problem
+//	@Failure		412			{object}	Problem
+//	@Failure		428			{object}	Problem
+//	@Router			/articles/{id} [put]
+func (a *Application) ReplaceArticle(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		renderProblem(w, r, http.StatusNotFound, "Article Not Found")
+		return
+	}
+
+	article, err := a.repo.GetArticleByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, database.ErrArticleNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Article Not Found")
+			return
+		}
+		a.logger.Error("Replace Article: " + err.Error())
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	if !canModifyArticle(user, article, database.PermArticlesUpdate, database.PermArticlesUpdateAny) {
+		renderProblem(w, r, http.StatusForbidden, "You are not allowed to modify this article")
+		return
+	}
+
+	var payload ReplaceArticleRequest
+	if err := decodeStrictJSON(r.Body, &payload); err != nil {
+		renderProblem(w, r, http.StatusBadRequest, "Please provide a valid JSON body: "+err.Error())
+		return
+	}
+
+	a.saveArticle(w, r, article, payload.ArticleDocument, payload.Version)
+}
+
+// patchArticle applies a JSON Merge Patch or a JSON Patch to the article's document. A version member
+// of a merge patch names the version being patched instead of being applied.
+func patchArticle(r *http.Request, mediaType string, article *database.Article) (ArticleDocument, *int, error) {
+	var doc ArticleDocument
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return doc, nil, err
+	}
+
+	original, err := json.Marshal(newArticleDocument(article))
+	if err != nil {
+		return doc, nil, err
+	}
+
+	var version *int
+	var patched []byte
+
+	switch mediaType {
+	case MERGE_PATCH_CONTENT_TYPE:
+		var patch map[string]json.RawMessage
+		if err := json.Unmarshal(body, &patch); err != nil {
+			return doc, nil, errors.New("a merge patch must be a JSON object")
+		}
+
+		if rawVersion, ok := patch["version"]; ok {
+			if err := json.Unmarshal(rawVersion, &version); err != nil {
+				return doc, nil, errors.New("version must be an integer")
+			}
+			delete(patch, "version")
+
+			if body, err = json.Marshal(patch); err != nil {
+				return doc, nil, err
+			}
+		}
+
+		if patched, err = jsonpatch.MergePatch(original, body); err != nil {
+			return doc, nil, err
+		}
+
+	case JSON_PATCH_CONTENT_TYPE:
+		patch, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			return doc, nil, err
+		}
+
+		patched, err = patch.Apply(original)
+		if err != nil {
+			if errors.Is(err, jsonpatch.ErrTestFailed) || errors.Is(err, jsonpatch.ErrMissing) || errors.Is(err, jsonpatch.ErrInvalidIndex) {
+				return doc, nil, errors.Join(errPatchConflict, err)
+			}
+			return doc, nil, err
+		}
+	}
+
+	if err := decodeStrictJSON(bytes.NewReader(patched), &doc); err != nil {
+		return doc, nil, err
+	}
+
+	return doc, version, nil
+}
+
+// saveArticle replaces the article's document with doc, after checking the version the client expects,
+// the permission to schedule the article and the resulting article
+func (a *Application) saveArticle(w http.ResponseWriter, r *http.Request, article *database.Article, doc ArticleDocument, version *int) {
+	if !checkArticleVersion(w, r, article, version) {
+		return
+	}
+
+	if !sameTime(doc.PublishAt, article.PublishAt) {
+		user, _ := userFromContext(r.Context())
+		if !canModifyArticle(user, article, database.PermArticlesPublish, database.PermArticlesPublishAny) {
+			renderProblem(w, r, http.StatusForbidden, "You are not allowed to schedule this article")
+			return
+		}
+
+		if doc.PublishAt != nil {
+			if article.Status != database.ArticleStatusDraft {
+				renderProblem(w, r, http.StatusConflict, "Only draft articles can be scheduled")
+				return
+			}
+
+			if !doc.PublishAt.After(time.Now()) {
+				renderValidationError(w, r, validation.Errors{"publish_at": errors.New("must be in the future")})
+				return
+			}
+		}
+	}
+
+	article.Title = doc.Title
+	article.Content = doc.Content
+	article.ContentFormat = doc.ContentFormat
+	article.Tags = doc.Tags
+	article.PublishAt = doc.PublishAt
+
+	if article.Tags == nil {
+		article.Tags = database.Tags{}
+	}
+
+	if err := article.Validate(); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	updatedArticle, err := a.repo.UpdateArticle(r.Context(), article, &user.ID)
+	if err != nil {
+		if errors.Is(err, database.ErrArticleVersionConflict) {
+			renderVersionConflict(w, r)
+			return
+		}
+
+		a.logger.Error("Save Article: " + err.Error())
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		return
+	}
+
+	data := UpdateArticleResponse{Article: *updatedArticle}
+	w.Header().Set("ETag", articleETag(updatedArticle))
+	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, nil))
+}
+
+// patchMediaType returns the media type of a PATCH body, requests without a Content-Type are treated as JSON
+func patchMediaType(r *http.Request) string {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "application/json"
+	}
+
+	return mediaType
+}
+
+func decodeStrictJSON(body io.Reader, dest interface{}) error {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(dest)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.Equal(*b)
+}
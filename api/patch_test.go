@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchArticle(t *testing.T) {
+	article := &database.Article{
+		Title:         "I love Golang",
+		Content:       "lorem ipsum",
+		ContentFormat: database.ContentFormatMarkdown,
+		Tags:          database.Tags{"go", "tech"},
+		Version:       3,
+	}
+
+	patch := func(mediaType, body string) (ArticleDocument, *int, error) {
+		r := httptest.NewRequest(http.MethodPatch, "/articles/1", strings.NewReader(body))
+		return patchArticle(r, mediaType, article)
+	}
+
+	t.Run("merge patch clears tags", func(t *testing.T) {
+		doc, version, err := patch(MERGE_PATCH_CONTENT_TYPE, `{"title": "I love Go", "tags": null, "version": 3}`)
+		require.NoError(t, err)
+		require.Equal(t, "I love Go", doc.Title)
+		require.Equal(t, "lorem ipsum", doc.Content)
+		require.Empty(t, doc.Tags)
+		require.Equal(t, 3, *version)
+	})
+
+	t.Run("merge patch without version", func(t *testing.T) {
+		doc, version, err := patch(MERGE_PATCH_CONTENT_TYPE, `{"tags": []}`)
+		require.NoError(t, err)
+		require.Empty(t, doc.Tags)
+		require.Nil(t, version)
+	})
+
+	t.Run("json patch", func(t *testing.T) {
+		doc, _, err := patch(JSON_PATCH_CONTENT_TYPE, `[
+			{"op": "test", "path": "/title", "value": "I love Golang"},
+			{"op": "remove", "path": "/tags/1"},
+			{"op": "add", "path": "/tags/-", "value": "web"}
+		]`)
+		require.NoError(t, err)
+		require.Equal(t, database.Tags{"go", "web"}, doc.Tags)
+	})
+
+	t.Run("failed test", func(t *testing.T) {
+		_, _, err := patch(JSON_PATCH_CONTENT_TYPE, `[{"op": "test", "path": "/title", "value": "I love Rust"}]`)
+		require.ErrorIs(t, err, errPatchConflict)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, _, err := patch(JSON_PATCH_CONTENT_TYPE, `[{"op": "add", "path": "/status", "value": "published"}]`)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, errPatchConflict)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, _, err := patch(MERGE_PATCH_CONTENT_TYPE, `["not", "an", "object"]`)
+		require.Error(t, err)
+	})
+}
+
+func TestReplaceArticleValidation(t *testing.T) {
+	author := &database.User{ID: 1, Permissions: []string{database.PermArticlesUpdate, database.PermArticlesPublish}}
+	app := &Application{
+		logger: slog.Default(),
+		repo: &fakeArticles{articles: map[int]*database.Article{
+			1: {ID: 1, Title: "I love Golang", Content: "lorem ipsum", AuthorID: &author.ID, Status: database.ArticleStatusDraft, Version: 3},
+		}},
+	}
+
+	body := `{"title": "I love Golang", "content": "lorem ipsum", "publish_at": "2020-01-01T00:00:00Z", "version": 3}`
+	r := httptest.NewRequest(http.MethodPut, "/articles/1", strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	r = r.WithContext(context.WithValue(context.WithValue(r.Context(), chi.RouteCtxKey, rctx), userContextKey, author))
+
+	w := httptest.NewRecorder()
+	app.ReplaceArticle(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var problem Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Equal(t, CODE_VALIDATION_FAILED, problem.Code)
+	require.Equal(t, map[string]string{"publish_at": "must be in the future"}, problem.Errors)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/utils"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// articleVersion names a version of an article. Single article reads send it in an ETag together with
+// a hash of the body, writes send it alone, and clients send either back in If-Match to update or
+// delete the version they read.
+func articleVersion(article *database.Article) string {
+	return "v" + strconv.Itoa(article.Version)
+}
+
+// articleETag is the ETag of an article sent with writes
+func articleETag(article *database.Article) string {
+	return `"` + articleVersion(article) + `"`
+}
+
+// checkArticleVersion makes sure the client is changing the version of the article it last read, named by
+// an If-Match header or by version. Requests with neither get a 428 and requests for an older version a 412.
+func checkArticleVersion(w http.ResponseWriter, r *http.Request, article *database.Article, version *int) bool {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" && version == nil {
+		renderProblem(w, r, http.StatusPreconditionRequired, "Send the article's ETag in If-Match or its version to change it")
+		return false
+	}
+
+	if (ifMatch != "" && !utils.MatchesIfMatch(r, articleVersion(article))) || (version != nil && *version != article.Version) {
+		w.Header().Set("ETag", articleETag(article))
+		renderVersionConflict(w, r)
+		return false
+	}
+
+	return true
+}
+
+// queryVersion reads the version a request without a body names in ?version=
+func queryVersion(w http.ResponseWriter, r *http.Request) (*int, bool) {
+	rawVersion := r.URL.Query().Get("version")
+	if rawVersion == "" {
+		return nil, true
+	}
+
+	version, err := strconv.Atoi(rawVersion)
+	if err != nil {
+		renderValidationError(w, r, validation.Errors{"version": errors.New("must be an integer")})
+		return nil, false
+	}
+
+	return &version, true
+}
+
+// renderVersionConflict tells the client the article changed since it was read
+func renderVersionConflict(w http.ResponseWriter, r *http.Request) {
+	renderProblem(w, r, http.StatusPreconditionFailed, "The article was changed since it was read, fetch it again and retry")
+}
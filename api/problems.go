@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	PROBLEM_CONTENT_TYPE = "application/problem+json"
+	PROBLEM_TYPE_PREFIX  = "urn:blogging-api:problem:"
+
+	CODE_VALIDATION_FAILED = "validation-failed"
+)
+
+// problemCodes are the codes of problems identified by their status alone
+var problemCodes = map[int]string{
+	http.StatusBadRequest:            "bad-request",
+	http.StatusUnauthorized:          "unauthorized",
+	http.StatusForbidden:             "forbidden",
+	http.StatusNotFound:              "not-found",
+	http.StatusMethodNotAllowed:      "method-not-allowed",
+	http.StatusConflict:              "conflict",
+	http.StatusPreconditionFailed:    "precondition-failed",
+	http.StatusRequestEntityTooLarge: "payload-too-large",
+	http.StatusUnsupportedMediaType:  "unsupported-media-type",
+	http.StatusPreconditionRequired:  "precondition-required",
+	http.StatusTooManyRequests:       "rate-limited",
+	http.StatusInternalServerError:   "internal-error",
+}
+
+func newProblem(r *http.Request, status int, code, detail string) *Problem {
+	return &Problem{
+		Type:      PROBLEM_TYPE_PREFIX + code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+}
+
+// renderProblem writes a problem whose code follows from its status
+func renderProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	code, ok := problemCodes[status]
+	if !ok {
+		code = strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "-")
+	}
+
+	writeProblem(w, newProblem(r, status, code, detail))
+}
+
+// renderValidationError writes a 400 for an invalid request. Errors from ozzo-validation are
+// broken down by field, anything else is reported as is.
+func renderValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	var fieldErrors validation.Errors
+	if !errors.As(err, &fieldErrors) {
+		renderProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	problem := newProblem(r, http.StatusBadRequest, CODE_VALIDATION_FAILED, "One or more fields are invalid")
+	problem.Errors = map[string]string{}
+	flattenErrors(problem.Errors, "", fieldErrors)
+
+	writeProblem(w, problem)
+}
+
+// flattenErrors adds the errors of nested structs and slices under keys joined by dots
+func flattenErrors(dest map[string]string, prefix string, fieldErrors validation.Errors) {
+	fields := make([]string, 0, len(fieldErrors))
+	for field := range fieldErrors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		key := field
+		if prefix != "" {
+			key = prefix + "." + field
+		}
+
+		var nested validation.Errors
+		if errors.As(fieldErrors[field], &nested) {
+			flattenErrors(dest, key, nested)
+			continue
+		}
+
+		dest[key] = fieldErrors[field].Error()
+	}
+}
+
+func writeProblem(w http.ResponseWriter, problem *Problem) {
+	w.Header().Set("Content-Type", PROBLEM_CONTENT_TYPE)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// exposeRequestID returns the ID given to the request by middleware.RequestID in an X-Request-Id header,
+// so clients can quote it when reporting a problem
+func exposeRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := middleware.GetReqID(r.Context()); id != "" {
+			w.Header().Set(middleware.RequestIDHeader, id)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
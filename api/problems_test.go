@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderProblem(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/articles/42", nil)
+	r = r.WithContext(context.WithValue(r.Context(), middleware.RequestIDKey, "host/abc-000001"))
+	w := httptest.NewRecorder()
+
+	renderProblem(w, r, http.StatusNotFound, "Article not found")
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, PROBLEM_CONTENT_TYPE, w.Header().Get("Content-Type"))
+	require.JSONEq(t, `{
+		"type": "urn:blogging-api:problem:not-found",
+		"title": "Not Found",
+		"status": 404,
+		"detail": "Article not found",
+		"instance": "/api/articles/42",
+		"code": "not-found",
+		"request_id": "host/abc-000001"
+	}`, w.Body.String())
+}
+
+func TestRenderValidationError(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/articles", nil)
+
+	t.Run("field errors", func(t *testing.T) {
+		article := &database.Article{Title: "Go", Content: "lorem ipsum", Tags: database.Tags{"go", "x"}}
+		w := httptest.NewRecorder()
+		renderValidationError(w, r, article.Validate())
+
+		var problem Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		require.Equal(t, http.StatusBadRequest, problem.Status)
+		require.Equal(t, CODE_VALIDATION_FAILED, problem.Code)
+		require.Equal(t, map[string]string{
+			"title":  "the length must be between 5 and 255",
+			"tags.1": "the length must be no less than 2",
+		}, problem.Errors)
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		renderValidationError(w, r, errors.New("render must be one of raw or html"))
+
+		var problem Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		require.Equal(t, "bad-request", problem.Code)
+		require.Equal(t, "render must be one of raw or html", problem.Detail)
+		require.Empty(t, problem.Errors)
+	})
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// PublishArticle godoc
+//	@Summary	Publish article
+//	@Tags		articles
+//	@Accept		json
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		id	path		int	true	"Article ID"
+//	@Success	200	{object}	SuccessReponse{data=UpdateArticleResponse}
+//	@Failure	401	{object}	Problem
+//	@Failure	403	{object}	Problem
+//	@Failure	404	{object}	Problem
+//	@Router		/articles/{id}/publish [post]
+func (a *Application) PublishArticle(w http.ResponseWriter, r *http.Request) {
+	a.changeArticleStatus(w, r, database.ArticleStatusPublished)
+}
+
+// UnpublishArticle godoc
+//	@Summary		Unpublish article
+//	@Description	Moves the article back to draft and clears its publication date
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Article ID"
+//	@Success		200	{object}	SuccessReponse{data=UpdateArticleResponse}
+//	@Failure		401	{object}	Problem
+//	@Failure		403	{object}	Problem
+//	@Failure		404	{object}	Problem
+//	@Router			/articles/{id}/unpublish [post]
+func (a *Application) UnpublishArticle(w http.ResponseWriter, r *http.Request) {
+	a.changeArticleStatus(w, r, database.ArticleStatusDraft)
+}
+
+// ArchiveArticle godoc
+//	@Summary	Archive article
+//	@Tags		articles
+//	@Accept		json
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		id	path		int	true	"Article ID"
+//	@Success	200	{object}	SuccessReponse{data=UpdateArticleResponse}
+//	@Failure	401	{object}	Problem
+//	@Failure	403	{object}	Problem
+//	@Failure	404	{object}	Problem
+//	@Router		/articles/{id}/archive [post]
+func (a *Application) ArchiveArticle(w http.ResponseWriter, r *http.Request) {
+	a.changeArticleStatus(w, r, database.ArticleStatusArchived)
+}
+
+func (a *Application) changeArticleStatus(w http.ResponseWriter, r *http.Request, status string) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		renderProblem(w, r, http.StatusNotFound, "Article Not Found")
+		return
+	}
+
+	article, err := a.repo.GetArticleByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, database.ErrArticleNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Article Not Found")
+			return
+		}
+		a.logger.Error("Change Article Status: " + err.Error())
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	if !canModifyArticle(user, article, database.PermArticlesPublish, database.PermArticlesPublishAny) {
+		renderProblem(w, r, http.StatusForbidden, "You are not allowed to modify this article")
+		return
+	}
+
+	// nothing to do, keep the original publication date
+	if article.Status == status {
+		data := UpdateArticleResponse{Article: *article}
+		utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, nil))
+		return
+	}
+
+	updatedArticle, err := a.repo.UpdateArticleStatus(r.Context(), id, status)
+	if err != nil {
+		a.logger.Error("Change Article Status: " + err.Error())
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		return
+	}
+
+	data := UpdateArticleResponse{Article: *updatedArticle}
+	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, nil))
+}
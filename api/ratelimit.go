@@ -0,0 +1,79 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ayo-awe/blogging_api/ratelimit"
+)
+
+// RateLimits configures how many requests a client can make, reads and writes are counted separately
+// so a client busy reading can still save its work
+type RateLimits struct {
+	Store  ratelimit.Store
+	Reads  ratelimit.Limit
+	Writes ratelimit.Limit
+}
+
+// RateLimit takes a token from the client's bucket for every request and rejects the request once the bucket is empty.
+// Clients sending a valid bearer token are counted per user, the others per IP address.
+func (a *Application) RateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		budget, limit := "write", a.limits.Writes
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			budget, limit = "read", a.limits.Reads
+		}
+
+		if a.limits.Store == nil || !limit.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		result, err := a.limits.Store.Take(r.Context(), budget+":"+a.rateLimitKey(r), limit)
+		if err != nil {
+			// an unavailable store should not take the API down with it
+			a.logger.Error("RateLimit: " + err.Error())
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Period)))
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			renderProblem(w, r, http.StatusTooManyRequests, fmt.Sprintf("Too many requests, retry in %d seconds", retryAfter))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitKey identifies the client. Only the token's signature is checked, so limiting costs no database lookup.
+func (a *Application) rateLimitKey(r *http.Request) string {
+	if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found && token != "" {
+		if userID, err := a.tokens.Verify(token); err == nil {
+			return "user:" + strconv.Itoa(userID)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/blogging_api/ratelimit"
+	"github.com/stretchr/testify/require"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+func TestRateLimit(t *testing.T) {
+	tokens := NewTokenIssuer("super-secret", time.Hour)
+	app := &Application{
+		logger: slog.Default(),
+		tokens: tokens,
+		limits: RateLimits{
+			Store:  ratelimit.NewMemory(),
+			Reads:  ratelimit.Limit{Requests: 2, Period: time.Minute},
+			Writes: ratelimit.Limit{Requests: 1, Period: time.Minute},
+		},
+	}
+	handler := app.RateLimit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(method, remoteAddr, authorization string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/articles", nil)
+		r.RemoteAddr = remoteAddr
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(http.MethodGet, "192.0.2.1:1234", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+	require.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+	// the port changes between connections, the client stays the same
+	require.Equal(t, http.StatusOK, serve(http.MethodGet, "192.0.2.1:5678", "").Code)
+
+	w = serve(http.MethodGet, "192.0.2.1:1234", "")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "30", w.Header().Get("Retry-After"))
+	require.Equal(t, PROBLEM_CONTENT_TYPE, w.Header().Get("Content-Type"))
+
+	t.Run("writes have their own budget", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, serve(http.MethodPost, "192.0.2.1:1234", "").Code)
+		require.Equal(t, http.StatusTooManyRequests, serve(http.MethodPost, "192.0.2.1:1234", "").Code)
+	})
+
+	t.Run("users are limited by token", func(t *testing.T) {
+		token, _, err := tokens.Issue(42)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusOK, serve(http.MethodGet, "192.0.2.1:1234", "Bearer "+token).Code)
+	})
+
+	t.Run("invalid tokens are limited by address", func(t *testing.T) {
+		require.Equal(t, http.StatusTooManyRequests, serve(http.MethodGet, "192.0.2.1:1234", "Bearer not-a-token").Code)
+	})
+
+	t.Run("disabled budgets are not limited", func(t *testing.T) {
+		app.limits.Writes = ratelimit.Limit{}
+		w := serve(http.MethodPost, "192.0.2.1:1234", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("requests go through when the store fails", func(t *testing.T) {
+		app.limits.Store = failingStore{}
+		require.Equal(t, http.StatusOK, serve(http.MethodGet, "192.0.2.1:1234", "").Code)
+	})
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/render"
+)
+
+const (
+	RENDER_RAW  = "raw"
+	RENDER_HTML = "html"
+)
+
+var errInvalidRender = errors.New("render must be one of raw or html")
+
+// parseRender reads the render query param, raw by default
+func parseRender(r *http.Request) (string, error) {
+	mode := r.URL.Query().Get("render")
+	switch mode {
+	case "":
+		return RENDER_RAW, nil
+	case RENDER_RAW, RENDER_HTML:
+		return mode, nil
+	}
+
+	return "", errInvalidRender
+}
+
+// renderArticles leaves content_html out of raw responses. For html responses it renders
+// articles saved before rendered HTML was stored.
+func (a *Application) renderArticles(mode string, articles []database.Article) {
+	for i := range articles {
+		a.renderArticle(mode, &articles[i])
+	}
+}
+
+func (a *Application) renderArticle(mode string, article *database.Article) {
+	if mode != RENDER_HTML {
+		article.ContentHTML = nil
+		return
+	}
+
+	if article.ContentHTML != nil {
+		return
+	}
+
+	contentHTML, err := article.RenderContent()
+	if err != nil {
+		a.logger.Error("renderArticle: " + err.Error())
+		return
+	}
+	article.ContentHTML = &contentHTML
+}
+
+// tableOfContents lists the headings of Markdown articles, plain text articles have none
+func tableOfContents(article *database.Article) []render.Heading {
+	if article.ContentFormat == database.ContentFormatPlain {
+		return []render.Heading{}
+	}
+
+	return render.TableOfContents(article.Content)
+}
+
+// GetHighlightCSS godoc
+//	@Summary		Stylesheet for highlighted code
+//	@Description	Colours the code blocks in content_html, which carry classes rather than inline styles.
+//	@Tags			articles
+//	@Produce		text/css
+//	@Success		200	{string}	string	"Stylesheet"
+//	@Router			/highlight.css [get]
+func (a *Application) GetHighlightCSS(w http.ResponseWriter, r *http.Request) {
+	css, err := render.HighlightCSS()
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetHighlightCSS: " + err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(css))
+}
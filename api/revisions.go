@@ -0,0 +1,236 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/diff"
+	"github.com/ayo-awe/blogging_api/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetArticleRevisions godoc
+//	@Summary	List article revisions
+//	@Tags		revisions
+//	@Accept		json
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		id			path		int	true	"Article ID"
+//	@Param		page		query		int	false	"Page"
+//	@Param		per_page	query		int	false	"Revisions per page"
+//	@Success	200			{object}	SuccessReponse{data=GetArticleRevisionsResponse,metadata=database.PaginationData}
+//	@Failure	401			{object}	Problem
+//	@Failure	403			{object}	Problem
+//	@Failure	404			{object}	Problem
+//	@Router		/articles/{id}/revisions [get]
+func (a *Application) GetArticleRevisions(w http.ResponseWriter, r *http.Request) {
+	article, ok := a.loadEditableArticle(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, paginationData, err := a.repo.GetArticleRevisions(r.Context(), article.ID, parsePaging(r))
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetArticleRevisions: " + err.Error())
+		return
+	}
+
+	data := GetArticleRevisionsResponse{Revisions: revisions}
+	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, paginationData))
+}
+
+// GetArticleRevision godoc
+//	@Summary	Get article revision
+//	@Tags		revisions
+//	@Accept		json
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		id	path		int	true	"Article ID"
+//	@Param		rev	path		int	true	"Revision number"
+//	@Success	200	{object}	SuccessReponse{data=GetArticleRevisionResponse}
+//	@Failure	401	{object}	Problem
+//	@Failure	403	{object}	Problem
+//	@Failure	404	{object}	Problem
+//	@Router		/articles/{id}/revisions/{rev} [get]
+func (a *Application) GetArticleRevision(w http.ResponseWriter, r *http.Request) {
+	article, ok := a.loadEditableArticle(w, r)
+	if !ok {
+		return
+	}
+
+	revision, ok := a.loadRevision(w, r, article.ID, chi.URLParam(r, "rev"))
+	if !ok {
+		return
+	}
+
+	data := GetArticleRevisionResponse{Revision: *revision}
+	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, nil))
+}
+
+// loadEditableArticle fetches the article in the id URL param and makes sure the caller may edit it.
+// Revisions can include unpublished content so they are limited to the article's editors.
+func (a *Application) loadEditableArticle(w http.ResponseWriter, r *http.Request) (*database.Article, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		renderProblem(w, r, http.StatusNotFound, "Article Not Found")
+		return nil, false
+	}
+
+	article, err := a.repo.GetArticleByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, database.ErrArticleNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Article Not Found")
+			return nil, false
+		}
+		a.logger.Error("Load Article: " + err.Error())
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		return nil, false
+	}
+
+	user, _ := userFromContext(r.Context())
+	if !canModifyArticle(user, article, database.PermArticlesUpdate, database.PermArticlesUpdateAny) {
+		renderProblem(w, r, http.StatusForbidden, "You are not allowed to view this article's history")
+		return nil, false
+	}
+
+	return article, true
+}
+
+func (a *Application) loadRevision(w http.ResponseWriter, r *http.Request, articleID int, rawRevision string) (*database.ArticleRevision, bool) {
+	rev, err := strconv.Atoi(rawRevision)
+	if err != nil {
+		renderProblem(w, r, http.StatusNotFound, "Revision not found")
+		return nil, false
+	}
+
+	revision, err := a.repo.GetArticleRevision(r.Context(), articleID, rev)
+	if err != nil {
+		if errors.Is(err, database.ErrRevisionNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Revision not found")
+			return nil, false
+		}
+		a.logger.Error("Load Revision: " + err.Error())
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		return nil, false
+	}
+
+	return revision, true
+}
+
+// DiffArticleRevisions godoc
+//	@Summary	Compare two article revisions
+//	@Tags		revisions
+//	@Accept		json
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		id		path		int		true	"Article ID"
+//	@Param		a		path		int		true	"Revision to compare from"
+//	@Param		b		path		int		true	"Revision to compare to"
+//	@Param		words	query		bool	false	"Include a word level diff of the content"
+//	@Success	200		{object}	SuccessReponse{data=RevisionDiffResponse}
+//	@Failure	401		{object}	Problem
+//	@Failure	403		{object}	Problem
+//	@Failure	404		{object}	Problem
+//	@Router		/articles/{id}/revisions/{a}/diff/{b} [get]
+func (a *Application) DiffArticleRevisions(w http.ResponseWriter, r *http.Request) {
+	article, ok := a.loadEditableArticle(w, r)
+	if !ok {
+		return
+	}
+
+	from, ok := a.loadRevision(w, r, article.ID, chi.URLParam(r, "a"))
+	if !ok {
+		return
+	}
+
+	to, ok := a.loadRevision(w, r, article.ID, chi.URLParam(r, "b"))
+	if !ok {
+		return
+	}
+
+	data := RevisionDiffResponse{
+		From: from.Revision,
+		To:   to.Revision,
+		Title: TitleDiff{
+			Changed: from.Title != to.Title,
+			From:    from.Title,
+			To:      to.Title,
+		},
+		Tags: TagsDiff{
+			Added:   tagsDifference(to.Tags, from.Tags),
+			Removed: tagsDifference(from.Tags, to.Tags),
+		},
+		Content: ContentDiff{
+			Unified: diff.Unified(from.Content, to.Content, revisionName(from), revisionName(to), 3),
+		},
+	}
+
+	if words, _ := strconv.ParseBool(r.URL.Query().Get("words")); words {
+		data.Content.Words = diff.Words(from.Content, to.Content)
+	}
+
+	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, nil))
+}
+
+// RestoreArticleRevision godoc
+//	@Summary		Restore article revision
+//	@Description	Copies the revision's title, content and tags onto the article as a new revision, history is never rewritten.
+//	@Description	Like other updates it must name the version of the article it changes.
+//	@Tags			revisions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int		true	"Article ID"
+//	@Param			rev			path		int		true	"Revision number"
+//	@Param			If-Match	header		string	false	"ETag of the version being replaced, required unless version is sent"
+//	@Param			version		query		int		false	"Version being replaced, required unless If-Match is sent"
+//	@Success		200			{object}	SuccessReponse{data=UpdateArticleResponse}
+//	@Failure		400			{object}	Problem
+//	@Failure		401			{object}	Problem
+//	@Failure		403			{object}	Problem
+//	@Failure		404			{object}	Problem
+//	@Failure		412			{object}	Problem
+//	@Failure		428			{object}	Problem
+//	@Router			/articles/{id}/revisions/{rev}/restore [post]
+func (a *Application) RestoreArticleRevision(w http.ResponseWriter, r *http.Request) {
+	article, ok := a.loadEditableArticle(w, r)
+	if !ok {
+		return
+	}
+
+	revision, ok := a.loadRevision(w, r, article.ID, chi.URLParam(r, "rev"))
+	if !ok {
+		return
+	}
+
+	version, ok := queryVersion(w, r)
+	if !ok {
+		return
+	}
+
+	doc := newArticleDocument(article)
+	doc.Title = revision.Title
+	doc.Content = revision.Content
+	doc.Tags = revision.Tags
+
+	a.saveArticle(w, r, article, doc, version)
+}
+
+func revisionName(revision *database.ArticleRevision) string {
+	return "revision " + strconv.Itoa(revision.Revision)
+}
+
+// tagsDifference returns the tags in a that are not in b
+func tagsDifference(a, b database.Tags) database.Tags {
+	difference := database.Tags{}
+	for _, tag := range a {
+		if !slices.Contains(b, tag) {
+			difference = append(difference, tag)
+		}
+	}
+	return difference
+}
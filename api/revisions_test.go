@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *fakeArticles) GetArticleRevision(ctx context.Context, articleID, revision int) (*database.ArticleRevision, error) {
+	for _, rev := range f.revisions {
+		if rev.ArticleID == articleID && rev.Revision == revision {
+			return rev, nil
+		}
+	}
+	return nil, database.ErrRevisionNotFound
+}
+
+func (f *fakeArticles) UpdateArticle(ctx context.Context, article *database.Article, editorID *int) (*database.Article, error) {
+	updated := *article
+	updated.Version++
+	f.articles[article.ID] = &updated
+	return &updated, nil
+}
+
+func TestRestoreArticleRevision(t *testing.T) {
+	author := &database.User{ID: 1, Permissions: []string{database.PermArticlesUpdate}}
+
+	restore := func(rev string, headers map[string]string, query string) *httptest.ResponseRecorder {
+		articles := &fakeArticles{
+			articles: map[int]*database.Article{
+				1: {ID: 1, Title: "I love Go", Content: "lorem ipsum", AuthorID: &author.ID, Version: 3},
+			},
+			revisions: []*database.ArticleRevision{
+				{ArticleID: 1, Revision: 1, Title: "I love Golang", Content: "lorem ipsum", Tags: database.Tags{"go"}},
+				{ArticleID: 1, Revision: 2, Title: "", Content: "saved before titles were required"},
+			},
+		}
+		app := &Application{logger: slog.Default(), repo: articles}
+
+		r := httptest.NewRequest(http.MethodPost, "/articles/1/revisions/"+rev+"/restore"+query, nil)
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "1")
+		rctx.URLParams.Add("rev", rev)
+		r = r.WithContext(context.WithValue(context.WithValue(r.Context(), chi.RouteCtxKey, rctx), userContextKey, author))
+
+		w := httptest.NewRecorder()
+		app.RestoreArticleRevision(w, r)
+		return w
+	}
+
+	t.Run("restores with the version", func(t *testing.T) {
+		w := restore("1", nil, "?version=3")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `"v4"`, w.Header().Get("ETag"))
+		require.Contains(t, w.Body.String(), `"title":"I love Golang"`)
+	})
+
+	t.Run("restores with If-Match", func(t *testing.T) {
+		w := restore("1", map[string]string{"If-Match": `"v3"`}, "")
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("version required", func(t *testing.T) {
+		w := restore("1", nil, "")
+		require.Equal(t, http.StatusPreconditionRequired, w.Code)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		w := restore("1", map[string]string{"If-Match": `"v2"`}, "")
+		require.Equal(t, http.StatusPreconditionFailed, w.Code)
+		require.Equal(t, `"v3"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("restored fields are validated", func(t *testing.T) {
+		w := restore("2", nil, "?version=3")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Contains(t, w.Body.String(), CODE_VALIDATION_FAILED)
+	})
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/sitemap"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetSitemap godoc
+//	@Summary		Sitemap of published articles
+//	@Description	Lists every published article with its last modification time. When articles have ids past 50,000 it is a
+//	@Description	sitemap index pointing to /sitemap-{page}.xml files instead, page n listing the articles with ids from n*50,000.
+//	@Tags			sitemap
+//	@Produce		xml
+//	@Success		200	{string}	string	"Sitemap or sitemap index"
+//	@Router			/sitemap.xml [get]
+func (a *Application) GetSitemap(w http.ResponseWriter, r *http.Request) {
+	pages, err := a.repo.GetSitemapPages(r.Context(), sitemap.MaxURLs)
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetSitemap: " + err.Error())
+		return
+	}
+
+	if len(pages) <= 1 {
+		page := 0
+		if len(pages) == 1 {
+			page = pages[0].Page
+		}
+
+		a.streamSitemapPage(w, r, page, false)
+		return
+	}
+
+	w.Header().Set("Content-Type", sitemap.ContentType)
+	index := sitemap.NewIndex(w)
+	for _, page := range pages {
+		loc := a.site.apiURL(fmt.Sprintf("/sitemap-%d.xml", page.Page))
+		if err := index.Add(loc, page.UpdatedAt); err != nil {
+			a.logger.Error("GetSitemap: " + err.Error())
+			return
+		}
+	}
+
+	if err := index.Close(); err != nil {
+		a.logger.Error("GetSitemap: " + err.Error())
+	}
+}
+
+// GetSitemapPage godoc
+//	@Summary	Page of the sitemap index
+//	@Tags		sitemap
+//	@Produce	xml
+//	@Param		page	path		int		true	"Page number, as linked from the sitemap index"
+//	@Success	200		{string}	string	"Sitemap"
+//	@Failure	404		{object}	Problem
+//	@Router		/sitemap-{page}.xml [get]
+func (a *Application) GetSitemapPage(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(chi.URLParam(r, "page"))
+	if err != nil {
+		renderProblem(w, r, http.StatusNotFound, "Sitemap not found")
+		return
+	}
+
+	a.streamSitemapPage(w, r, page, true)
+}
+
+// streamSitemapPage writes the articles on page to the response as they are read from the database.
+// Once streaming has started errors can only be logged, the client gets a truncated document.
+func (a *Application) streamSitemapPage(w http.ResponseWriter, r *http.Request, page int, requireEntries bool) {
+	w.Header().Set("Content-Type", sitemap.ContentType)
+	urlSet := sitemap.NewURLSet(w)
+
+	entries := 0
+	err := a.repo.EachSitemapEntry(r.Context(), page, sitemap.MaxURLs, func(entry database.SitemapEntry) error {
+		entries++
+		return urlSet.Add(a.site.articleURL(entry.Slug), entry.UpdatedAt)
+	})
+	if err != nil {
+		a.logger.Error("streamSitemapPage: " + err.Error())
+		return
+	}
+
+	// the writer buffers, so nothing has been sent yet for a page past the last article
+	if entries == 0 && requireEntries {
+		renderProblem(w, r, http.StatusNotFound, "Sitemap not found")
+		return
+	}
+
+	if err := urlSet.Close(); err != nil {
+		a.logger.Error("streamSitemapPage: " + err.Error())
+	}
+}
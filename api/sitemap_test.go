@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/sitemap"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *fakeArticles) EachSitemapEntry(ctx context.Context, page, pageSize int, fn func(database.SitemapEntry) error) error {
+	for id := page * pageSize; id < (page+1)*pageSize; id++ {
+		article, ok := f.articles[id]
+		if !ok || article.Status != database.ArticleStatusPublished {
+			continue
+		}
+
+		if err := fn(database.SitemapEntry{Slug: article.Slug, UpdatedAt: article.UpdatedAt}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeArticles) GetSitemapPages(ctx context.Context, pageSize int) ([]database.SitemapPage, error) {
+	pages := []database.SitemapPage{}
+	for id := range f.articles {
+		page := database.SitemapPage{Page: id / pageSize}
+		if !slices.Contains(pages, page) {
+			pages = append(pages, page)
+		}
+	}
+
+	slices.SortFunc(pages, func(a, b database.SitemapPage) int { return a.Page - b.Page })
+	return pages, nil
+}
+
+func TestGetSitemap(t *testing.T) {
+	app := &Application{
+		logger: slog.Default(),
+		site:   Site{BaseURL: "https://blog.example.com", APIURL: "https://api.example.com/api"},
+		repo: &fakeArticles{articles: map[int]*database.Article{
+			3:                   {ID: 3, Status: database.ArticleStatusPublished},
+			sitemap.MaxURLs + 3: {ID: sitemap.MaxURLs + 3, Status: database.ArticleStatusPublished},
+		}},
+	}
+
+	w := httptest.NewRecorder()
+	app.GetSitemap(w, httptest.NewRequest(http.MethodGet, "/api/sitemap.xml", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "<loc>https://api.example.com/api/sitemap-0.xml</loc>")
+	require.Contains(t, w.Body.String(), "<loc>https://api.example.com/api/sitemap-1.xml</loc>")
+}
+
+func TestGetSitemapPage(t *testing.T) {
+	app := &Application{
+		logger: slog.Default(),
+		site:   Site{BaseURL: "https://blog.example.com"},
+		repo: &fakeArticles{articles: map[int]*database.Article{
+			3: {ID: 3, Slug: "i-love-golang", Status: database.ArticleStatusPublished, UpdatedAt: time.Now()},
+		}},
+	}
+
+	get := func(page string) *httptest.ResponseRecorder {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("page", page)
+
+		r := httptest.NewRequest(http.MethodGet, "/sitemap-"+page+".xml", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		w := httptest.NewRecorder()
+		app.GetSitemapPage(w, r)
+		return w
+	}
+
+	w := get("0")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, sitemap.ContentType, w.Header().Get("Content-Type"))
+	require.Contains(t, w.Body.String(), "https://blog.example.com/articles/i-love-golang")
+
+	w = get("1")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, PROBLEM_CONTENT_TYPE, w.Header().Get("Content-Type"))
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetTags godoc
+//	@Summary		List tags
+//	@Description	Lists tags used by published articles with the number of published articles using each.
+//	@Tags			tags
+//	@Accept			json
+//	@Produce		json
+//	@Param			sort		query		string	false	"Sort order"	Enums(popular, name)	default(popular)
+//	@Param			page		query		int		false	"Page"
+//	@Param			per_page	query		int		false	"Tags per page"
+//	@Success		200			{object}	SuccessReponse{data=GetTagsResponse,metadata=database.PaginationData}
+//	@Success		304
+//	@Failure		400	{object}	Problem
+//	@Router			/tags [get]
+func (a *Application) GetTags(w http.ResponseWriter, r *http.Request) {
+	sort := r.URL.Query().Get("sort")
+	if sort == "" {
+		sort = database.TagSortPopular
+	}
+
+	if sort != database.TagSortPopular && sort != database.TagSortName {
+		renderProblem(w, r, http.StatusBadRequest, "sort must be one of popular or name")
+		return
+	}
+
+	tags, paginationData, err := a.tags.GetTags(r.Context(), sort, parsePaging(r))
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetTags: " + err.Error())
+		return
+	}
+
+	data := GetTagsResponse{Tags: tags}
+	utils.RenderConditionalResponse(w, r, NewSuccessResponse(data, paginationData), time.Time{})
+}
+
+// GetTagBySlug godoc
+//	@Summary		Get tag by slug
+//	@Description	Returns the tag with a page of the published articles using it, newest first.
+//	@Tags			tags
+//	@Accept			json
+//	@Produce		json
+//	@Param			slug		path		string	true	"Tag slug"
+//	@Param			page		query		int		false	"Page"
+//	@Param			per_page	query		int		false	"Articles per page"
+//	@Param			render		query		string	false	"Include content rendered to sanitized HTML as content_html"	Enums(raw, html)	default(raw)
+//	@Success		200			{object}	SuccessReponse{data=GetTagResponse,metadata=database.PaginationData}
+//	@Success		304
+//	@Failure		400	{object}	Problem
+//	@Failure		404	{object}	Problem
+//	@Router			/tags/{slug} [get]
+func (a *Application) GetTagBySlug(w http.ResponseWriter, r *http.Request) {
+	renderMode, err := parseRender(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	tag, ok := a.loadTag(w, r)
+	if !ok {
+		return
+	}
+
+	filter := database.ArticleFilter{
+		Tags:   database.Tags{tag.Name},
+		Status: database.ArticleStatusPublished,
+	}
+
+	articles, paginationData, err := a.repo.GetArticles(r.Context(), filter, parsePaging(r))
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetTagBySlug: " + err.Error())
+		return
+	}
+
+	a.renderArticles(renderMode, articles)
+	data := GetTagResponse{Tag: *tag, Articles: articles}
+	utils.RenderConditionalResponse(w, r, NewSuccessResponse(data, paginationData), time.Time{})
+}
+
+// UpdateTag godoc
+//	@Summary	Update a tag's description
+//	@Tags		tags
+//	@Accept		json
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		slug	path		string				true	"Tag slug"
+//	@Param		data	body		UpdateTagRequest	true	"Request Body"
+//	@Success	200		{object}	SuccessReponse{data=UpdateTagResponse}
+//	@Failure	400		{object}	Problem
+//	@Failure	401		{object}	Problem
+//	@Failure	403		{object}	Problem
+//	@Failure	404		{object}	Problem
+//	@Router		/tags/{slug} [patch]
+func (a *Application) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	var payload UpdateTagRequest
+	if err := utils.DecodeJSON(r, &payload); err != nil {
+		renderProblem(w, r, http.StatusBadRequest, "Please provide a valid JSON body")
+		return
+	}
+
+	if err := payload.Validate(); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	tag := &database.Tag{Slug: chi.URLParam(r, "slug"), Description: payload.Description}
+	tag, err := a.tags.UpdateTag(r.Context(), tag)
+	if err != nil {
+		if errors.Is(err, database.ErrTagNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Tag not found")
+			return
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("UpdateTag: " + err.Error())
+		return
+	}
+
+	data := UpdateTagResponse{Tag: *tag}
+	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, nil))
+}
+
+// loadTag fetches the tag named by the slug URL parameter, rendering a 404 when it does not exist
+func (a *Application) loadTag(w http.ResponseWriter, r *http.Request) (*database.Tag, bool) {
+	tag, err := a.tags.GetTagBySlug(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		if errors.Is(err, database.ErrTagNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Tag not found")
+			return nil, false
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("loadTag: " + err.Error())
+		return nil, false
+	}
+
+	return tag, true
+}
+
+// RenameTag godoc
+//	@Summary		Rename a tag
+//	@Description	Renames the tag in every article in a single transaction, recording a revision of each. Renaming to an existing tag merges the two.
+//	@Tags			tags
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			slug	path		string				true	"Tag slug"
+//	@Param			data	body		RenameTagRequest	true	"Request Body"
+//	@Success		200		{object}	SuccessReponse{data=MergeTagsResponse}
+//	@Failure		400		{object}	Problem
+//	@Failure		401		{object}	Problem
+//	@Failure		403		{object}	Problem
+//	@Failure		404		{object}	Problem
+//	@Router			/tags/{slug}/rename [post]
+func (a *Application) RenameTag(w http.ResponseWriter, r *http.Request) {
+	var payload RenameTagRequest
+	if err := utils.DecodeJSON(r, &payload); err != nil {
+		renderProblem(w, r, http.StatusBadRequest, "Please provide a valid JSON body")
+		return
+	}
+
+	if err := payload.Validate(); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	a.mergeTags(w, r, []string{chi.URLParam(r, "slug")}, payload.Name)
+}
+
+// MergeTags godoc
+//	@Summary		Merge tags
+//	@Description	Replaces the given tags with a single tag in every article in a single transaction, removing duplicates and recording a revision of each.
+//	@Tags			tags
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			data	body		MergeTagsRequest	true	"Request Body"
+//	@Success		200		{object}	SuccessReponse{data=MergeTagsResponse}
+//	@Failure		400		{object}	Problem
+//	@Failure		401		{object}	Problem
+//	@Failure		403		{object}	Problem
+//	@Failure		404		{object}	Problem
+//	@Router			/tags/merge [post]
+func (a *Application) MergeTags(w http.ResponseWriter, r *http.Request) {
+	var payload MergeTagsRequest
+	if err := utils.DecodeJSON(r, &payload); err != nil {
+		renderProblem(w, r, http.StatusBadRequest, "Please provide a valid JSON body")
+		return
+	}
+
+	if err := payload.Validate(); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	a.mergeTags(w, r, payload.Tags, payload.Into)
+}
+
+func (a *Application) mergeTags(w http.ResponseWriter, r *http.Request, slugs []string, into string) {
+	user, _ := userFromContext(r.Context())
+	tag, articleCount, err := a.tags.MergeTags(r.Context(), slugs, into, &user.ID)
+	if err != nil {
+		if errors.Is(err, database.ErrTagNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "Tag not found")
+			return
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("mergeTags: " + err.Error())
+		return
+	}
+
+	data := MergeTagsResponse{Tag: *tag, ArticlesUpdated: articleCount}
+	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, nil))
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+type TokenIssuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewTokenIssuer(secret string, ttl time.Duration) *TokenIssuer {
+	return &TokenIssuer{secret: []byte(secret), ttl: ttl}
+}
+
+// Issue returns a signed token identifying the user along with its expiry time
+func (t *TokenIssuer) Issue(userID int) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(t.ttl)
+
+	claims := jwt.RegisteredClaims{
+		Subject:   strconv.Itoa(userID),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(t.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// Verify checks the token's signature and expiry and returns the user ID it was issued for
+func (t *TokenIssuer) Verify(token string) (int, error) {
+	var claims jwt.RegisteredClaims
+
+	keyFn := func(*jwt.Token) (interface{}, error) { return t.secret, nil }
+	_, err := jwt.ParseWithClaims(token, &claims, keyFn, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	return userID, nil
+}
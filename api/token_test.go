@@ -0,0 +1,43 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenIssuer(t *testing.T) {
+	issuer := NewTokenIssuer("super-secret", time.Hour)
+
+	t.Run("issue and verify", func(t *testing.T) {
+		token, expiresAt, err := issuer.Issue(42)
+		require.NoError(t, err)
+		require.True(t, expiresAt.After(time.Now()))
+
+		userID, err := issuer.Verify(token)
+		require.NoError(t, err)
+		require.Equal(t, 42, userID)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		token, _, err := NewTokenIssuer("another-secret", time.Hour).Issue(42)
+		require.NoError(t, err)
+
+		_, err = issuer.Verify(token)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		token, _, err := NewTokenIssuer("super-secret", -time.Minute).Issue(42)
+		require.NoError(t, err)
+
+		_, err = issuer.Verify(token)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		_, err := issuer.Verify("not-a-token")
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
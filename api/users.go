@@ -0,0 +1,124 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ayo-awe/blogging_api/database"
+	"github.com/ayo-awe/blogging_api/utils"
+	"github.com/go-chi/chi/v5"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// GetUsers godoc
+//	@Summary	List users
+//	@Tags		users
+//	@Accept		json
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		page		query		int	false	"Page"
+//	@Param		per_page	query		int	false	"Users per page"
+//	@Success	200			{object}	SuccessReponse{data=GetUsersResponse,metadata=database.PaginationData}
+//	@Failure	401			{object}	Problem
+//	@Failure	403			{object}	Problem
+//	@Router		/users [get]
+func (a *Application) GetUsers(w http.ResponseWriter, r *http.Request) {
+	users, paginationData, err := a.users.GetUsers(r.Context(), parsePaging(r))
+	if err != nil {
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetUsers: " + err.Error())
+		return
+	}
+
+	data := GetUsersResponse{Users: users}
+	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, paginationData))
+}
+
+// GetUserByID godoc
+//	@Summary	Get user by ID
+//	@Tags		users
+//	@Accept		json
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		id	path		int	true	"User ID"
+//	@Success	200	{object}	SuccessReponse{data=GetUserByIDResponse}
+//	@Failure	401	{object}	Problem
+//	@Failure	403	{object}	Problem
+//	@Failure	404	{object}	Problem
+//	@Router		/users/{id} [get]
+func (a *Application) GetUserByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		renderProblem(w, r, http.StatusNotFound, "User not found")
+		return
+	}
+
+	user, err := a.users.GetUserByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, database.ErrUserNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "User not found")
+			return
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("GetUserByID: " + err.Error())
+		return
+	}
+
+	data := GetUserByIDResponse{User: *user}
+	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, nil))
+}
+
+// UpdateUserRole godoc
+//	@Summary	Change a user's role
+//	@Tags		users
+//	@Accept		json
+//	@Produce	json
+//	@Security	BearerAuth
+//	@Param		id		path		int						true	"User ID"
+//	@Param		data	body		UpdateUserRoleRequest	true	"Request Body"
+//	@Success	200		{object}	SuccessReponse{data=GetUserByIDResponse}
+//	@Failure	400		{object}	Problem
+//	@Failure	401		{object}	Problem
+//	@Failure	403		{object}	Problem
+//	@Failure	404		{object}	Problem
+//	@Router		/users/{id}/role [patch]
+func (a *Application) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		renderProblem(w, r, http.StatusNotFound, "User not found")
+		return
+	}
+
+	var payload UpdateUserRoleRequest
+	if err = utils.DecodeJSON(r, &payload); err != nil {
+		renderProblem(w, r, http.StatusBadRequest, "Please provide a valid JSON body")
+		return
+	}
+
+	if err = payload.Validate(); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	user, err := a.users.UpdateUserRole(r.Context(), id, payload.Role)
+	if err != nil {
+		if errors.Is(err, database.ErrUserNotFound) {
+			renderProblem(w, r, http.StatusNotFound, "User not found")
+			return
+		}
+
+		if errors.Is(err, database.ErrInvalidRole) {
+			renderValidationError(w, r, validation.Errors{"role": errors.New("unknown role")})
+			return
+		}
+
+		renderProblem(w, r, http.StatusInternalServerError, "An unexpected error occured")
+		a.logger.Error("UpdateUserRole: " + err.Error())
+		return
+	}
+
+	data := GetUserByIDResponse{User: *user}
+	utils.RenderResponse(w, http.StatusOK, NewSuccessResponse(data, nil))
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"slices"
+	"time"
 
+	"github.com/ayo-awe/blogging_api/slug"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 )
@@ -15,57 +18,227 @@ type articleRepo struct {
 
 var (
 	ErrArticleNotFound = errors.New("article not found")
+	// ErrArticleVersionConflict is returned when an article changed after the version being updated or deleted was read
+	ErrArticleVersionConflict = errors.New("article version conflict")
 )
 
 const (
+	// articleColumns are the columns scanned into an Article.
+	// Queries using it must alias the article row as a and join its author as u.
+	articleColumns = `
+		a.id,
+		a.title,
+		a.slug,
+		a.content,
+		a.content_format,
+		a.content_html,
+		a.tags,
+		a.author_id,
+		u.name AS author_name,
+		a.status,
+		a.revision,
+		a.version,
+		a.publish_at,
+		a.published_at,
+		a.created_at,
+		a.updated_at`
+
 	createArticle = `
-	INSERT INTO "articles" (title, content, tags)
-	VALUES ($1, $2, $3) RETURNING *`
+	WITH inserted AS (
+		INSERT INTO "articles" (title, content, tags, author_id, publish_at, slug, content_format, content_html)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *
+	)
+	SELECT` + articleColumns + `
+	FROM inserted a
+	LEFT JOIN "users" u ON u.id = a.author_id;`
+
+	// escapedContent is the article's source with HTML escaped, so the <mark> tags added to
+	// snippets are the only markup they contain
+	escapedContent = `replace(replace(replace(a.content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;')`
 
 	getArticles = `
-	SELECT
-		id,
-		title,
-		content,
-		tags,
-		published_at,
-		updated_at
-	FROM "articles"
-	WHERE tags ?| $1 OR $1 = '{}' OR $1 IS NULL
-	ORDER BY published_at DESC
+	SELECT` + articleColumns + `,
+		CASE WHEN $6 = '' THEN NULL ELSE ts_headline('english', ` + escapedContent + `, query,
+			'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')
+		END AS snippet
+	FROM "articles" a
+	LEFT JOIN "users" u ON u.id = a.author_id
+	CROSS JOIN websearch_to_tsquery('english', $6::text) AS query
+	WHERE ($1 = '{}' OR $1 IS NULL OR CASE WHEN $7 = 'all' THEN a.tags ?& $1 ELSE a.tags ?| $1 END)
+		AND ($8 IS NULL OR NOT a.tags ?| $8)
+		AND ($4::text = '' OR a.status = $4)
+		AND ($5::int IS NULL OR a.author_id = $5)
+		AND ($6 = '' OR a.search_vector @@ query)
+	ORDER BY
+		CASE WHEN $6 = '' THEN 0 ELSE ts_rank(a.search_vector, query) END DESC,
+		COALESCE(a.published_at, a.created_at) DESC,
+		a.id DESC
 	LIMIT $2
 	OFFSET $3;`
 
+	// getArticlesAfter and getArticlesBefore walk the (sort time, id) keyset in opposite directions.
+	// The first page is requested with a NULL cursor.
+	getArticlesAfter = `
+	SELECT` + articleColumns + `
+	FROM "articles" a
+	LEFT JOIN "users" u ON u.id = a.author_id
+	WHERE ($1 = '{}' OR $1 IS NULL OR CASE WHEN $7 = 'all' THEN a.tags ?& $1 ELSE a.tags ?| $1 END)
+		AND ($8 IS NULL OR NOT a.tags ?| $8)
+		AND ($2::text = '' OR a.status = $2)
+		AND ($3::int IS NULL OR a.author_id = $3)
+		AND ($4::timestamptz IS NULL OR (COALESCE(a.published_at, a.created_at), a.id) < ($4, $5::int))
+	ORDER BY COALESCE(a.published_at, a.created_at) DESC, a.id DESC
+	LIMIT $6;`
+
+	getArticlesBefore = `
+	SELECT` + articleColumns + `
+	FROM "articles" a
+	LEFT JOIN "users" u ON u.id = a.author_id
+	WHERE ($1 = '{}' OR $1 IS NULL OR CASE WHEN $7 = 'all' THEN a.tags ?& $1 ELSE a.tags ?| $1 END)
+		AND ($8 IS NULL OR NOT a.tags ?| $8)
+		AND ($2::text = '' OR a.status = $2)
+		AND ($3::int IS NULL OR a.author_id = $3)
+		AND (COALESCE(a.published_at, a.created_at), a.id) > ($4::timestamptz, $5::int)
+	ORDER BY COALESCE(a.published_at, a.created_at) ASC, a.id ASC
+	LIMIT $6;`
+
 	countArticles = `
 	SELECT
 		count(*)
 	FROM "articles"
-	WHERE tags ?| $1 OR $1 = '{}' OR $1 IS NULL;`
+	WHERE ($1 = '{}' OR $1 IS NULL OR CASE WHEN $5 = 'all' THEN tags ?& $1 ELSE tags ?| $1 END)
+		AND ($6 IS NULL OR NOT tags ?| $6)
+		AND ($2::text = '' OR status = $2)
+		AND ($3::int IS NULL OR author_id = $3)
+		AND ($4::text = '' OR search_vector @@ websearch_to_tsquery('english', $4));`
 
 	getArticleByID = `
-	SELECT
-		id,
-		title,
-		content,
-		tags,
-		published_at,
-		updated_at
+	SELECT` + articleColumns + `
+	FROM "articles" a
+	LEFT JOIN "users" u ON u.id = a.author_id
+	WHERE a.id = $1;`
+
+	// getArticleBySlug also finds articles by the slugs they used to have
+	getArticleBySlug = `
+	SELECT` + articleColumns + `
+	FROM "articles" a
+	LEFT JOIN "users" u ON u.id = a.author_id
+	WHERE a.slug = $1
+		OR a.id = (SELECT article_id FROM "article_slugs" WHERE slug = $1);`
+
+	lockArticle = `
+	SELECT title, slug, version
 	FROM "articles"
-	WHERE id = $1;`
+	WHERE id = $1
+	FOR UPDATE;`
+
+	// getSimilarArticleSlugs finds the current and former slugs of other articles that
+	// a slug generated as $1 could collide with
+	getSimilarArticleSlugs = `
+	SELECT slug
+	FROM "articles"
+	WHERE (slug = $1 OR slug LIKE $1 || '-%') AND id <> $2
+	UNION
+	SELECT slug
+	FROM "article_slugs"
+	WHERE (slug = $1 OR slug LIKE $1 || '-%') AND article_id <> $2;`
+
+	insertArticleSlug = `
+	INSERT INTO "article_slugs" (slug, article_id)
+	VALUES ($1, $2)
+	ON CONFLICT (slug) DO NOTHING;`
+
+	deleteArticleSlug = `
+	DELETE FROM "article_slugs"
+	WHERE slug = $1;`
 
 	updateArticle = `
-	UPDATE "articles"
+	WITH updated AS (
+		UPDATE "articles"
+		SET
+			title = $2,
+			content = $3,
+			tags = $4,
+			publish_at = $5,
+			slug = $6,
+			content_format = $7,
+			content_html = $8,
+			revision = revision + 1,
+			version = version + 1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *
+	)
+	SELECT` + articleColumns + `
+	FROM updated a
+	LEFT JOIN "users" u ON u.id = a.author_id;`
+
+	updateArticleStatus = `
+	WITH updated AS (
+		UPDATE "articles"
+		SET
+			status = $2,
+			published_at = CASE
+				WHEN $2 = 'published' THEN CURRENT_TIMESTAMP
+				WHEN $2 = 'draft' THEN NULL
+				ELSE published_at
+			END,
+			publish_at = NULL,
+			version = version + 1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *
+	)
+	SELECT` + articleColumns + `
+	FROM updated a
+	LEFT JOIN "users" u ON u.id = a.author_id;`
+
+	publishDueArticles = `
+	WITH due AS (
+		SELECT id
+		FROM "articles"
+		WHERE status = 'draft'
+			AND publish_at IS NOT NULL
+			AND publish_at <= CURRENT_TIMESTAMP
+		ORDER BY publish_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	UPDATE "articles" a
 	SET
-		title = $2,
-		content = $3,
-		tags = $4,
+		status = 'published',
+		published_at = a.publish_at,
+		publish_at = NULL,
+		version = a.version + 1,
 		updated_at = CURRENT_TIMESTAMP
-	WHERE id = $1
-	RETURNING *;`
+	FROM due
+	WHERE a.id = due.id
+	RETURNING a.id;`
+
+	// getSitemapPages returns the pages of $1 ids that have published articles and when each last changed.
+	// Pages are ranges of ids, so an article stays on the same page whatever is published or unpublished.
+	getSitemapPages = `
+	SELECT
+		id / $1 AS page,
+		max(updated_at) AS updated_at
+	FROM "articles"
+	WHERE status = 'published'
+	GROUP BY page
+	ORDER BY page;`
+
+	// getSitemapEntries reads page $1 of $2 ids by id range, so later pages don't scan the earlier ones
+	getSitemapEntries = `
+	SELECT slug, updated_at
+	FROM "articles"
+	WHERE status = 'published' AND id >= $1 * $2 AND id < ($1 + 1) * $2
+	ORDER BY id;`
 
 	deleteArticle = `
 	DELETE FROM "articles"
-	WHERE id = $1;`
+	WHERE id = $1 AND version = $2;`
+
+	articleExists = `
+	SELECT EXISTS (SELECT 1 FROM "articles" WHERE id = $1);`
 )
 
 func NewArticleRepository(database Database) ArticleRepository {
@@ -75,13 +248,38 @@ func NewArticleRepository(database Database) ArticleRepository {
 func (repo *articleRepo) CreateArticle(ctx context.Context, article *Article) (*Article, error) {
 	newArticle := &Article{}
 
-	row := repo.db.QueryRowxContext(ctx, createArticle,
-		article.Title,
-		article.Content,
-		article.Tags,
-	)
+	err := withSlugTx(ctx, repo.db, func(tx *sqlx.Tx) error {
+		articleSlug, err := uniqueArticleSlug(ctx, tx, article.Title, 0)
+		if err != nil {
+			return err
+		}
+
+		contentFormat, contentHTML, err := renderContent(article)
+		if err != nil {
+			return err
+		}
+
+		row := tx.QueryRowxContext(ctx, createArticle,
+			article.Title,
+			article.Content,
+			article.Tags,
+			article.AuthorID,
+			article.PublishAt,
+			articleSlug,
+			contentFormat,
+			contentHTML,
+		)
 
-	err := row.StructScan(newArticle)
+		if err := row.StructScan(newArticle); err != nil {
+			return err
+		}
+
+		if err := syncTags(ctx, tx, newArticle.Tags); err != nil {
+			return err
+		}
+
+		return insertRevision(ctx, tx, newArticle, newArticle.AuthorID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -90,10 +288,20 @@ func (repo *articleRepo) CreateArticle(ctx context.Context, article *Article) (*
 }
 
 func (repo *articleRepo) GetArticles(ctx context.Context, filter ArticleFilter, paging Paging) ([]Article, PaginationData, error) {
-	rows, err := repo.db.QueryxContext(ctx, getArticles, pq.Array(filter.Tags), paging.Limit(), paging.Offset())
+	rows, err := repo.db.QueryxContext(ctx, getArticles,
+		pq.Array(filter.Tags),
+		paging.Limit(),
+		paging.Offset(),
+		filter.Status,
+		filter.AuthorID,
+		filter.Query,
+		filter.TagsMode,
+		pq.Array(filter.ExcludeTags),
+	)
 	if err != nil {
 		return []Article{}, PaginationData{}, err
 	}
+	defer rows.Close()
 
 	articles := []Article{}
 	for rows.Next() {
@@ -107,7 +315,14 @@ func (repo *articleRepo) GetArticles(ctx context.Context, filter ArticleFilter,
 	}
 
 	var articleCount int
-	if err = repo.db.QueryRowContext(ctx, countArticles, pq.Array(filter.Tags)).Scan(&articleCount); err != nil {
+	if err = repo.db.QueryRowContext(ctx, countArticles,
+		pq.Array(filter.Tags),
+		filter.Status,
+		filter.AuthorID,
+		filter.Query,
+		filter.TagsMode,
+		pq.Array(filter.ExcludeTags),
+	).Scan(&articleCount); err != nil {
 		return []Article{}, PaginationData{}, err
 	}
 
@@ -117,6 +332,70 @@ func (repo *articleRepo) GetArticles(ctx context.Context, filter ArticleFilter,
 	return articles, paginationData, nil
 }
 
+func (repo *articleRepo) GetArticlesByCursor(ctx context.Context, filter ArticleFilter, paging CursorPaging) ([]Article, CursorPaginationData, error) {
+	query := getArticlesAfter
+	var sortAt *time.Time
+	var lastID int
+
+	if paging.Cursor != nil {
+		sortAt, lastID = &paging.Cursor.SortAt, paging.Cursor.ID
+		if paging.Cursor.Before {
+			query = getArticlesBefore
+		}
+	}
+
+	// fetch one extra row to find out whether there is another page in this direction
+	articles := []Article{}
+	err := repo.db.SelectContext(ctx, &articles, query,
+		pq.Array(filter.Tags),
+		filter.Status,
+		filter.AuthorID,
+		sortAt,
+		lastID,
+		paging.Limit+1,
+		filter.TagsMode,
+		pq.Array(filter.ExcludeTags),
+	)
+	if err != nil {
+		return []Article{}, CursorPaginationData{}, err
+	}
+
+	hasMore := len(articles) > paging.Limit
+	if hasMore {
+		articles = articles[:paging.Limit]
+	}
+
+	backwards := paging.Cursor != nil && paging.Cursor.Before
+	if backwards {
+		slices.Reverse(articles)
+	}
+
+	paginationData := CursorPaginationData{ItemCount: len(articles), Limit: paging.Limit}
+	if len(articles) == 0 {
+		return articles, paginationData, nil
+	}
+
+	// moving forward there is a previous page unless this is the first one,
+	// moving backwards there is always a next page, the one we came from
+	hasNext, hasPrev := hasMore, paging.Cursor != nil
+	if backwards {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	first, last := articles[0], articles[len(articles)-1]
+	if hasNext {
+		next := Cursor{SortAt: last.sortAt(), ID: last.ID}.Encode()
+		paginationData.NextCursor = &next
+	}
+
+	if hasPrev {
+		prev := Cursor{SortAt: first.sortAt(), ID: first.ID, Before: true}.Encode()
+		paginationData.PrevCursor = &prev
+	}
+
+	return articles, paginationData, nil
+}
+
 func (repo *articleRepo) GetArticleByID(ctx context.Context, ID int) (*Article, error) {
 	var article Article
 
@@ -131,27 +410,245 @@ func (repo *articleRepo) GetArticleByID(ctx context.Context, ID int) (*Article,
 	return &article, nil
 }
 
-func (repo *articleRepo) UpdateArticle(ctx context.Context, article *Article) (*Article, error) {
+// GetArticleBySlug finds an article by its current or a former slug,
+// callers can compare the slug asked for with the article's to tell them apart
+func (repo *articleRepo) GetArticleBySlug(ctx context.Context, slug string) (*Article, error) {
+	var article Article
+
+	err := repo.db.QueryRowxContext(ctx, getArticleBySlug, slug).StructScan(&article)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrArticleNotFound
+		}
+		return nil, err
+	}
+
+	return &article, nil
+}
+
+// UpdateArticle saves the article and records the new state as a revision made by editorID
+func (repo *articleRepo) UpdateArticle(ctx context.Context, article *Article, editorID *int) (*Article, error) {
 	var updatedArticle Article
 
-	row := repo.db.QueryRowxContext(ctx, updateArticle,
-		article.ID,
-		article.Title,
-		article.Content,
-		article.Tags)
+	err := withSlugTx(ctx, repo.db, func(tx *sqlx.Tx) error {
+		current, err := lockArticleForUpdate(ctx, tx, article.ID)
+		if err != nil {
+			return err
+		}
+
+		if current.Version != article.Version {
+			return ErrArticleVersionConflict
+		}
+
+		articleSlug, err := updateArticleSlug(ctx, tx, article, current)
+		if err != nil {
+			return err
+		}
 
-	if err := row.StructScan(&updatedArticle); err != nil {
+		contentFormat, contentHTML, err := renderContent(article)
+		if err != nil {
+			return err
+		}
+
+		row := tx.QueryRowxContext(ctx, updateArticle,
+			article.ID,
+			article.Title,
+			article.Content,
+			article.Tags,
+			article.PublishAt,
+			articleSlug,
+			contentFormat,
+			contentHTML)
+
+		if err := row.StructScan(&updatedArticle); err != nil {
+			return err
+		}
+
+		if err := syncTags(ctx, tx, updatedArticle.Tags); err != nil {
+			return err
+		}
+
+		return insertRevision(ctx, tx, &updatedArticle, editorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updatedArticle, nil
+}
+
+func (repo *articleRepo) UpdateArticleStatus(ctx context.Context, ID int, status string) (*Article, error) {
+	var updatedArticle Article
+
+	err := repo.db.QueryRowxContext(ctx, updateArticleStatus, ID, status).StructScan(&updatedArticle)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrArticleNotFound
+		}
 		return nil, err
 	}
 
 	return &updatedArticle, nil
 }
 
-func (repo *articleRepo) DeleteArticle(ctx context.Context, ID int) error {
-	_, err := repo.db.ExecContext(ctx, deleteArticle, ID)
+// PublishDueArticles publishes up to limit drafts whose publish_at has passed and returns their IDs.
+// Rows already claimed by another replica are skipped rather than waited on.
+func (repo *articleRepo) PublishDueArticles(ctx context.Context, limit int) ([]int, error) {
+	var IDs []int
+
+	if err := repo.db.SelectContext(ctx, &IDs, publishDueArticles, limit); err != nil {
+		return nil, err
+	}
+
+	return IDs, nil
+}
+
+func (repo *articleRepo) GetSitemapPages(ctx context.Context, pageSize int) ([]SitemapPage, error) {
+	pages := []SitemapPage{}
+
+	if err := repo.db.SelectContext(ctx, &pages, getSitemapPages, pageSize); err != nil {
+		return nil, err
+	}
+
+	return pages, nil
+}
+
+// EachSitemapEntry calls fn for the published articles on page, as returned by GetSitemapPages,
+// reading one row at a time. It stops at the first error fn returns.
+func (repo *articleRepo) EachSitemapEntry(ctx context.Context, page, pageSize int, fn func(SitemapEntry) error) error {
+	rows, err := repo.db.QueryxContext(ctx, getSitemapEntries, page, pageSize)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry SitemapEntry
+		if err := rows.StructScan(&entry); err != nil {
+			return err
+		}
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (repo *articleRepo) DeleteArticle(ctx context.Context, ID, version int) error {
+	result, err := repo.db.ExecContext(ctx, deleteArticle, ID, version)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	deleted, err := result.RowsAffected()
+	if err != nil || deleted > 0 {
+		return err
+	}
+
+	var exists bool
+	if err := repo.db.GetContext(ctx, &exists, articleExists, ID); err != nil {
+		return err
+	}
+
+	if exists {
+		return ErrArticleVersionConflict
+	}
+
+	return ErrArticleNotFound
+}
+
+// renderContent returns the article's content format, defaulting to Markdown, and its content rendered to HTML
+func renderContent(article *Article) (string, string, error) {
+	contentFormat := article.ContentFormat
+	if contentFormat == "" {
+		contentFormat = ContentFormatMarkdown
+	}
+
+	contentHTML, err := article.RenderContent()
+	if err != nil {
+		return "", "", err
+	}
+
+	return contentFormat, contentHTML, nil
+}
+
+// uniqueArticleSlug slugifies title, adding a numeric suffix when the slug is or was used by an article other than articleID
+func uniqueArticleSlug(ctx context.Context, tx *sqlx.Tx, title string, articleID int) (string, error) {
+	base := slug.Make(title)
+	if base == "" {
+		base = "article"
+	}
+
+	var taken []string
+	if err := tx.SelectContext(ctx, &taken, getSimilarArticleSlugs, base, articleID); err != nil {
+		return "", err
+	}
+
+	return slug.Unique(base, taken), nil
+}
+
+// withSlugTx runs fn in a transaction like withTx, starting over in a new transaction when
+// a concurrent one committed the slug fn picked, since the failed insert aborts the first.
+func withSlugTx(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	for attempt := 1; ; attempt++ {
+		err := withTx(ctx, db, fn)
+		if !isArticleSlugViolation(err) {
+			return err
+		}
+
+		if attempt == MAX_SLUG_ATTEMPTS {
+			return ErrSlugConflict
+		}
+	}
+}
+
+func isArticleSlugViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "articles_slug_key"
+}
+
+// lockedArticle is the state of an article locked for an update
+type lockedArticle struct {
+	Title   string `db:"title"`
+	Slug    string `db:"slug"`
+	Version int    `db:"version"`
+}
+
+func lockArticleForUpdate(ctx context.Context, tx *sqlx.Tx, ID int) (*lockedArticle, error) {
+	var current lockedArticle
+
+	if err := tx.GetContext(ctx, &current, lockArticle, ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrArticleNotFound
+		}
+		return nil, err
+	}
+
+	return &current, nil
+}
+
+// updateArticleSlug returns the slug the article should have after an update. When a new title
+// changes the slug, the old one is kept in the article's slug history so links to it still resolve.
+func updateArticleSlug(ctx context.Context, tx *sqlx.Tx, article *Article, current *lockedArticle) (string, error) {
+	if article.Title == current.Title {
+		return current.Slug, nil
+	}
+
+	articleSlug, err := uniqueArticleSlug(ctx, tx, article.Title, article.ID)
+	if err != nil || articleSlug == current.Slug {
+		return articleSlug, err
+	}
+
+	if _, err := tx.ExecContext(ctx, insertArticleSlug, current.Slug, article.ID); err != nil {
+		return "", err
+	}
+
+	// the article may be going back to one of its former slugs
+	if _, err := tx.ExecContext(ctx, deleteArticleSlug, articleSlug); err != nil {
+		return "", err
+	}
+
+	return articleSlug, nil
 }
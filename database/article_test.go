@@ -4,7 +4,9 @@ import (
 	"context"
 	"math"
 	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ayo-awe/blogging_api/utils"
 	_ "github.com/lib/pq"
@@ -48,6 +50,54 @@ func TestCreateArticle(t *testing.T) {
 		require.NotNil(t, article.Tags)
 	})
 
+	t.Run("create renders markdown", func(t *testing.T) {
+		payload := &Article{
+			Title:   "Deep learning for dummies",
+			Content: "Learn **deep** learning<script>alert(1)</script>",
+		}
+
+		article, err := repo.CreateArticle(context.Background(), payload)
+		require.NoError(t, err)
+
+		require.Equal(t, ContentFormatMarkdown, article.ContentFormat)
+		require.NotNil(t, article.ContentHTML)
+		require.Equal(t, "<p>Learn <strong>deep</strong> learning</p>\n", *article.ContentHTML)
+	})
+
+	t.Run("create plain text", func(t *testing.T) {
+		payload := &Article{
+			Title:         "Deep learning for dummies",
+			Content:       "Learn **deep** learning",
+			ContentFormat: ContentFormatPlain,
+		}
+
+		article, err := repo.CreateArticle(context.Background(), payload)
+		require.NoError(t, err)
+
+		require.Equal(t, ContentFormatPlain, article.ContentFormat)
+		require.Equal(t, "<p>Learn **deep** learning</p>\n", *article.ContentHTML)
+	})
+
+	t.Run("create with author", func(t *testing.T) {
+		author, err := NewUserRepository(db).CreateUser(context.Background(), &User{
+			Name:         "Jane Doe",
+			Email:        "jane@example.com",
+			PasswordHash: "not-a-real-hash",
+		})
+		require.NoError(t, err)
+
+		payload := &Article{
+			Title:    "Deep learning for dummies",
+			Content:  "Learn deep learning",
+			AuthorID: &author.ID,
+		}
+
+		article, err := repo.CreateArticle(context.Background(), payload)
+		require.NoError(t, err)
+
+		require.Equal(t, author.ID, *article.AuthorID)
+		require.Equal(t, author.Name, *article.AuthorName)
+	})
 }
 
 func TestGetArticles(t *testing.T) {
@@ -125,6 +175,29 @@ func TestGetArticles(t *testing.T) {
 		require.Len(t, foundArticles, len(articles))
 	})
 
+	t.Run("filter by all tags", func(t *testing.T) {
+		foundArticles, _, err := repo.GetArticles(context.Background(), ArticleFilter{Tags: Tags{"golang", "programming"}, TagsMode: TagsModeAll}, Paging{
+			Page:    1,
+			PerPage: 20,
+		})
+		require.NoError(t, err)
+		require.Len(t, foundArticles, 1)
+		require.Equal(t, "Golang for dummies", foundArticles[0].Title)
+	})
+
+	t.Run("exclude tags", func(t *testing.T) {
+		foundArticles, _, err := repo.GetArticles(context.Background(), ArticleFilter{Tags: Tags{"golang", "food"}, ExcludeTags: Tags{"programming", "slushy"}}, Paging{
+			Page:    1,
+			PerPage: 20,
+		})
+		require.NoError(t, err)
+		require.Len(t, foundArticles, 2)
+
+		for _, article := range foundArticles {
+			require.False(t, slices.Contains(article.Tags, "programming") || slices.Contains(article.Tags, "slushy"))
+		}
+	})
+
 	paginationTestCases := []struct {
 		name               string
 		expectedTotalItems int
@@ -139,7 +212,19 @@ func TestGetArticles(t *testing.T) {
 		{
 			name:               "page with filter",
 			expectedTotalItems: 2,
-			filter:             ArticleFilter{Tags{"go"}},
+			filter:             ArticleFilter{Tags: Tags{"go"}},
+			perPage:            2,
+		},
+		{
+			name:               "page with all tags filter",
+			expectedTotalItems: 1,
+			filter:             ArticleFilter{Tags: Tags{"food", "cooking"}, TagsMode: TagsModeAll},
+			perPage:            2,
+		},
+		{
+			name:               "page with excluded tags",
+			expectedTotalItems: 3,
+			filter:             ArticleFilter{ExcludeTags: Tags{"go"}},
 			perPage:            2,
 		},
 	}
@@ -231,13 +316,102 @@ func TestUpdateArticle(t *testing.T) {
 	createdArticle.Content = "Just do it"
 	createdArticle.Tags = Tags{"oats", "cooking", "food"}
 
-	updatedArticle, err := repo.UpdateArticle(context.Background(), createdArticle)
+	updatedArticle, err := repo.UpdateArticle(context.Background(), createdArticle, nil)
 	require.NoError(t, err)
 	require.Equal(t, createdArticle.Title, updatedArticle.Title)
 	require.Equal(t, createdArticle.Content, updatedArticle.Content)
 	require.Equal(t, createdArticle.Tags, updatedArticle.Tags)
 	require.NotEqual(t, createdArticle.UpdatedAt, updatedArticle.UpdatedAt)
+	require.Equal(t, createdArticle.Version+1, updatedArticle.Version)
+
+	t.Run("stale version", func(t *testing.T) {
+		createdArticle.Title = "How to cook rice"
+		_, err := repo.UpdateArticle(context.Background(), createdArticle, nil)
+		require.ErrorIs(t, err, ErrArticleVersionConflict)
+
+		article, err := repo.GetArticleByID(context.Background(), createdArticle.ID)
+		require.NoError(t, err)
+		require.Equal(t, "How to cook oats", article.Title)
+	})
+
+	t.Run("status changes bump the version", func(t *testing.T) {
+		published, err := repo.UpdateArticleStatus(context.Background(), createdArticle.ID, ArticleStatusPublished)
+		require.NoError(t, err)
+		require.Equal(t, updatedArticle.Version+1, published.Version)
+	})
+}
+
+func TestArticleSlugs(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	repo := NewArticleRepository(db)
+
+	first, err := repo.CreateArticle(context.Background(), &Article{Title: "I love Golang", Content: "lorem ipsum"})
+	require.NoError(t, err)
+	require.Equal(t, "i-love-golang", first.Slug)
+
+	second, err := repo.CreateArticle(context.Background(), &Article{Title: "I love Golang!", Content: "lorem ipsum"})
+	require.NoError(t, err)
+	require.Equal(t, "i-love-golang-2", second.Slug)
 
+	t.Run("find by slug", func(t *testing.T) {
+		article, err := repo.GetArticleBySlug(context.Background(), "i-love-golang-2")
+		require.NoError(t, err)
+		require.Equal(t, second.ID, article.ID)
+
+		_, err = repo.GetArticleBySlug(context.Background(), "i-love-rust")
+		require.ErrorIs(t, err, ErrArticleNotFound)
+	})
+
+	t.Run("new title keeps the old slug around", func(t *testing.T) {
+		first.Title = "I love Go"
+		updated, err := repo.UpdateArticle(context.Background(), first, nil)
+		require.NoError(t, err)
+		require.Equal(t, "i-love-go", updated.Slug)
+
+		article, err := repo.GetArticleBySlug(context.Background(), "i-love-golang")
+		require.NoError(t, err)
+		require.Equal(t, first.ID, article.ID)
+		require.Equal(t, "i-love-go", article.Slug)
+	})
+
+	t.Run("former slugs are not reused", func(t *testing.T) {
+		third, err := repo.CreateArticle(context.Background(), &Article{Title: "I love golang", Content: "lorem ipsum"})
+		require.NoError(t, err)
+		require.Equal(t, "i-love-golang-3", third.Slug)
+	})
+
+	t.Run("same slug after a title change", func(t *testing.T) {
+		second.Title = "I LOVE GOLANG"
+		updated, err := repo.UpdateArticle(context.Background(), second, nil)
+		require.NoError(t, err)
+		require.Equal(t, "i-love-golang-2", updated.Slug)
+	})
+
+	t.Run("concurrent creates get their own slugs", func(t *testing.T) {
+		var wg sync.WaitGroup
+		created := make([]*Article, 10)
+		errs := make([]error, len(created))
+
+		for i := range created {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				created[i], errs[i] = repo.CreateArticle(context.Background(), &Article{Title: "Hello world", Content: "lorem ipsum"})
+			}()
+		}
+
+		wg.Wait()
+
+		slugs := map[string]bool{}
+		for i, article := range created {
+			require.NoError(t, errs[i])
+			slugs[article.Slug] = true
+		}
+		require.Len(t, slugs, len(created))
+		require.True(t, slugs["hello-world"])
+	})
 }
 
 func TestDeleteArticle(t *testing.T) {
@@ -255,8 +429,294 @@ func TestDeleteArticle(t *testing.T) {
 	article, err := repo.CreateArticle(context.Background(), &payload)
 	require.NoError(t, err)
 
-	require.NoError(t, repo.DeleteArticle(context.Background(), article.ID))
+	err = repo.DeleteArticle(context.Background(), article.ID, article.Version+1)
+	require.ErrorIs(t, err, ErrArticleVersionConflict)
+
+	require.NoError(t, repo.DeleteArticle(context.Background(), article.ID, article.Version))
 
 	_, err = repo.GetArticleByID(context.Background(), article.ID)
 	require.ErrorIs(t, err, ErrArticleNotFound)
+
+	err = repo.DeleteArticle(context.Background(), article.ID, article.Version)
+	require.ErrorIs(t, err, ErrArticleNotFound)
+}
+
+func TestUpdateArticleStatus(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	repo := NewArticleRepository(db)
+
+	article, err := repo.CreateArticle(context.Background(), &Article{
+		Title:   "How to bake bread",
+		Content: "Just do it",
+	})
+	require.NoError(t, err)
+	require.Equal(t, ArticleStatusDraft, article.Status)
+	require.Nil(t, article.PublishedAt)
+
+	t.Run("drafts are not listed as published", func(t *testing.T) {
+		foundArticles, _, err := repo.GetArticles(context.Background(), ArticleFilter{Status: ArticleStatusPublished}, Paging{Page: 1, PerPage: 20})
+		require.NoError(t, err)
+		require.Empty(t, foundArticles)
+	})
+
+	t.Run("publish", func(t *testing.T) {
+		publishedArticle, err := repo.UpdateArticleStatus(context.Background(), article.ID, ArticleStatusPublished)
+		require.NoError(t, err)
+		require.Equal(t, ArticleStatusPublished, publishedArticle.Status)
+		require.NotNil(t, publishedArticle.PublishedAt)
+
+		foundArticles, _, err := repo.GetArticles(context.Background(), ArticleFilter{Status: ArticleStatusPublished}, Paging{Page: 1, PerPage: 20})
+		require.NoError(t, err)
+		require.Len(t, foundArticles, 1)
+	})
+
+	t.Run("archive keeps publication date", func(t *testing.T) {
+		archivedArticle, err := repo.UpdateArticleStatus(context.Background(), article.ID, ArticleStatusArchived)
+		require.NoError(t, err)
+		require.Equal(t, ArticleStatusArchived, archivedArticle.Status)
+		require.NotNil(t, archivedArticle.PublishedAt)
+	})
+
+	t.Run("unpublish clears publication date", func(t *testing.T) {
+		draftArticle, err := repo.UpdateArticleStatus(context.Background(), article.ID, ArticleStatusDraft)
+		require.NoError(t, err)
+		require.Equal(t, ArticleStatusDraft, draftArticle.Status)
+		require.Nil(t, draftArticle.PublishedAt)
+	})
+
+	t.Run("article not found", func(t *testing.T) {
+		_, err := repo.UpdateArticleStatus(context.Background(), 0, ArticleStatusPublished)
+		require.ErrorIs(t, err, ErrArticleNotFound)
+	})
+}
+
+func TestPublishDueArticles(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	repo := NewArticleRepository(db)
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	dueArticle, err := repo.CreateArticle(context.Background(), &Article{
+		Title:     "Due article",
+		Content:   "Scheduled in the past",
+		PublishAt: &past,
+	})
+	require.NoError(t, err)
+
+	scheduledArticle, err := repo.CreateArticle(context.Background(), &Article{
+		Title:     "Scheduled article",
+		Content:   "Scheduled in the future",
+		PublishAt: &future,
+	})
+	require.NoError(t, err)
+
+	IDs, err := repo.PublishDueArticles(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, []int{dueArticle.ID}, IDs)
+
+	publishedArticle, err := repo.GetArticleByID(context.Background(), dueArticle.ID)
+	require.NoError(t, err)
+	require.Equal(t, ArticleStatusPublished, publishedArticle.Status)
+	require.NotNil(t, publishedArticle.PublishedAt)
+	require.Nil(t, publishedArticle.PublishAt)
+
+	stillScheduled, err := repo.GetArticleByID(context.Background(), scheduledArticle.ID)
+	require.NoError(t, err)
+	require.Equal(t, ArticleStatusDraft, stillScheduled.Status)
+
+	IDs, err = repo.PublishDueArticles(context.Background(), 10)
+	require.NoError(t, err)
+	require.Empty(t, IDs)
+}
+
+func TestSearchArticles(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	repo := NewArticleRepository(db)
+	articles := []Article{
+		{
+			Title:   "Concurrency in Go",
+			Content: "Goroutines and channels make concurrent programs simple",
+			Tags:    Tags{"golang"},
+		},
+		{
+			Title:   "Baking sourdough",
+			Content: "A guide to bread, with a short note on concurrency in the kitchen",
+			Tags:    Tags{"food"},
+		},
+		{
+			Title:   "How to make a slushy",
+			Content: "Ice, syrup and patience",
+			Tags:    Tags{"food"},
+		},
+	}
+
+	for _, article := range articles {
+		_, err := repo.CreateArticle(context.Background(), &article)
+		require.NoError(t, err)
+	}
+
+	paging := Paging{Page: 1, PerPage: 20}
+
+	t.Run("title matches rank first", func(t *testing.T) {
+		foundArticles, paginationData, err := repo.GetArticles(context.Background(), ArticleFilter{Query: "concurrency"}, paging)
+		require.NoError(t, err)
+		require.Len(t, foundArticles, 2)
+		require.Equal(t, 2, paginationData.TotalItems)
+		require.Equal(t, "Concurrency in Go", foundArticles[0].Title)
+
+		for _, article := range foundArticles {
+			require.NotNil(t, article.Snippet)
+			require.Contains(t, *article.Snippet, "<mark>")
+		}
+	})
+
+	t.Run("combined with tags", func(t *testing.T) {
+		foundArticles, _, err := repo.GetArticles(context.Background(), ArticleFilter{Query: "concurrency", Tags: Tags{"food"}}, paging)
+		require.NoError(t, err)
+		require.Len(t, foundArticles, 1)
+		require.Equal(t, "Baking sourdough", foundArticles[0].Title)
+	})
+
+	t.Run("snippets escape the content", func(t *testing.T) {
+		_, err := repo.CreateArticle(context.Background(), &Article{
+			Title:   "Embedding widgets",
+			Content: `Paste <script>alert("widget")</script> and <img src=x onerror=alert(1)> into the page`,
+		})
+		require.NoError(t, err)
+
+		foundArticles, _, err := repo.GetArticles(context.Background(), ArticleFilter{Query: "alert widget"}, paging)
+		require.NoError(t, err)
+		require.Len(t, foundArticles, 1)
+		require.NotNil(t, foundArticles[0].Snippet)
+
+		snippet := *foundArticles[0].Snippet
+		require.Contains(t, snippet, "<mark>")
+		require.Contains(t, snippet, "&lt;script&gt;")
+		require.NotContains(t, snippet, "<script")
+		require.NotContains(t, snippet, "<img")
+	})
+
+	t.Run("no snippet without query", func(t *testing.T) {
+		foundArticles, _, err := repo.GetArticles(context.Background(), ArticleFilter{}, paging)
+		require.NoError(t, err)
+		require.Len(t, foundArticles, len(articles))
+		require.Nil(t, foundArticles[0].Snippet)
+	})
+}
+
+func TestGetArticlesByCursor(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	repo := NewArticleRepository(db)
+
+	var IDs []int
+	for i := 0; i < 5; i++ {
+		article, err := repo.CreateArticle(context.Background(), &Article{
+			Title:   "Keyset pagination",
+			Content: "Walking through pages",
+		})
+		require.NoError(t, err)
+		IDs = append(IDs, article.ID)
+	}
+	slices.Reverse(IDs)
+
+	collectIDs := func(articles []Article) []int {
+		return utils.Map(articles, func(a Article) int { return a.ID })
+	}
+
+	firstPage, firstData, err := repo.GetArticlesByCursor(context.Background(), ArticleFilter{}, CursorPaging{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, IDs[:2], collectIDs(firstPage))
+	require.Nil(t, firstData.PrevCursor)
+	require.NotNil(t, firstData.NextCursor)
+
+	cursor, err := DecodeCursor(*firstData.NextCursor)
+	require.NoError(t, err)
+
+	secondPage, secondData, err := repo.GetArticlesByCursor(context.Background(), ArticleFilter{}, CursorPaging{Cursor: cursor, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, IDs[2:4], collectIDs(secondPage))
+	require.NotNil(t, secondData.PrevCursor)
+	require.NotNil(t, secondData.NextCursor)
+
+	cursor, err = DecodeCursor(*secondData.NextCursor)
+	require.NoError(t, err)
+
+	lastPage, lastData, err := repo.GetArticlesByCursor(context.Background(), ArticleFilter{}, CursorPaging{Cursor: cursor, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, IDs[4:], collectIDs(lastPage))
+	require.Nil(t, lastData.NextCursor)
+
+	cursor, err = DecodeCursor(*lastData.PrevCursor)
+	require.NoError(t, err)
+
+	backPage, backData, err := repo.GetArticlesByCursor(context.Background(), ArticleFilter{}, CursorPaging{Cursor: cursor, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, IDs[2:4], collectIDs(backPage))
+	require.NotNil(t, backData.PrevCursor)
+	require.NotNil(t, backData.NextCursor)
+}
+
+func TestSitemap(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	repo := NewArticleRepository(db)
+
+	var published []*Article
+	for i := 0; i < 5; i++ {
+		article, err := repo.CreateArticle(context.Background(), &Article{Title: "How to bake bread", Content: "Just do it"})
+		require.NoError(t, err)
+
+		if i == 2 {
+			continue // stays a draft
+		}
+
+		article, err = repo.UpdateArticleStatus(context.Background(), article.ID, ArticleStatusPublished)
+		require.NoError(t, err)
+		published = append(published, article)
+	}
+
+	// ids keep counting across tests, so the pages are worked out from them
+	expected := map[int][]string{}
+	for _, article := range published {
+		expected[article.ID/3] = append(expected[article.ID/3], article.Slug)
+	}
+
+	pages, err := repo.GetSitemapPages(context.Background(), 3)
+	require.NoError(t, err)
+	require.Len(t, pages, len(expected))
+
+	for _, page := range pages {
+		var slugs []string
+		err := repo.EachSitemapEntry(context.Background(), page.Page, 3, func(entry SitemapEntry) error {
+			slugs = append(slugs, entry.Slug)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected[page.Page], slugs)
+	}
+
+	t.Run("pages keep their articles", func(t *testing.T) {
+		_, err := repo.UpdateArticleStatus(context.Background(), published[0].ID, ArticleStatusDraft)
+		require.NoError(t, err)
+
+		var slugs []string
+		err = repo.EachSitemapEntry(context.Background(), published[3].ID/3, 3, func(entry SitemapEntry) error {
+			slugs = append(slugs, entry.Slug)
+			return nil
+		})
+		require.NoError(t, err)
+
+		unpublished := published[0].Slug
+		require.Equal(t, slices.DeleteFunc(expected[published[3].ID/3], func(slug string) bool { return slug == unpublished }), slugs)
+	})
 }
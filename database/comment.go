@@ -0,0 +1,278 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type commentRepo struct {
+	db *sqlx.DB
+}
+
+var (
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrCommentDeleted  = errors.New("comment deleted")
+)
+
+const (
+	// commentColumns are the columns scanned into a Comment.
+	// Queries using it must alias the comment row as c and join its author as u.
+	commentColumns = `
+		c.id,
+		c.article_id,
+		c.parent_id,
+		c.user_id,
+		COALESCE(u.name, c.guest_name) AS author_name,
+		c.body,
+		c.status,
+		c.deleted_at IS NOT NULL AS deleted,
+		c.created_at,
+		c.updated_at`
+
+	// isListed holds for the comments shown in threads. Deleted comments are only kept while a reply below
+	// them, through approved comments, is still shown. Queries using it must alias the comment row as c.
+	isListed = `
+		c.status = 'approved'
+		AND (c.deleted_at IS NULL OR EXISTS (
+			WITH RECURSIVE below AS (
+				SELECT reply.id, reply.deleted_at
+				FROM "comments" reply
+				WHERE reply.parent_id = c.id AND reply.status = 'approved'
+
+				UNION ALL
+
+				SELECT reply.id, reply.deleted_at
+				FROM "comments" reply
+				JOIN below b ON reply.parent_id = b.id
+				WHERE reply.status = 'approved'
+			)
+			SELECT 1 FROM below WHERE deleted_at IS NULL
+		))`
+
+	createComment = `
+	WITH inserted AS (
+		INSERT INTO "comments" (article_id, parent_id, user_id, guest_name, body, status)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING *
+	)
+	SELECT` + commentColumns + `
+	FROM inserted c
+	LEFT JOIN "users" u ON u.id = c.user_id;`
+
+	// getComments pages through approved top level comments and returns each one
+	// followed by its approved replies, depth first
+	getComments = `
+	WITH RECURSIVE roots AS (
+		SELECT c.id
+		FROM "comments" c
+		WHERE c.article_id = $1
+			AND c.parent_id IS NULL
+			AND` + isListed + `
+		ORDER BY c.created_at, c.id
+		LIMIT $2
+		OFFSET $3
+	), thread AS (
+		SELECT c.*, 0 AS depth, ARRAY[c.id] AS path
+		FROM "comments" c
+		JOIN roots r ON r.id = c.id
+
+		UNION ALL
+
+		SELECT c.*, t.depth + 1, t.path || c.id
+		FROM "comments" c
+		JOIN thread t ON c.parent_id = t.id
+		WHERE` + isListed + `
+	)
+	SELECT` + commentColumns + `,
+		c.depth
+	FROM thread c
+	LEFT JOIN "users" u ON u.id = c.user_id
+	ORDER BY c.path;`
+
+	countComments = `
+	SELECT
+		count(*)
+	FROM "comments" c
+	WHERE c.article_id = $1
+		AND c.parent_id IS NULL
+		AND` + isListed + `;`
+
+	getCommentsByStatus = `
+	SELECT` + commentColumns + `
+	FROM "comments" c
+	LEFT JOIN "users" u ON u.id = c.user_id
+	WHERE c.status = $1 AND c.deleted_at IS NULL
+	ORDER BY c.created_at, c.id
+	LIMIT $2
+	OFFSET $3;`
+
+	countCommentsByStatus = `
+	SELECT
+		count(*)
+	FROM "comments"
+	WHERE status = $1 AND deleted_at IS NULL;`
+
+	getCommentByID = `
+	SELECT` + commentColumns + `
+	FROM "comments" c
+	LEFT JOIN "users" u ON u.id = c.user_id
+	WHERE c.article_id = $1 AND c.id = $2;`
+
+	updateCommentStatus = `
+	WITH updated AS (
+		UPDATE "comments"
+		SET
+			status = $2,
+			moderated_by = $3,
+			moderated_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING *
+	)
+	SELECT` + commentColumns + `
+	FROM updated c
+	LEFT JOIN "users" u ON u.id = c.user_id;`
+
+	commentDeleted = `
+	SELECT deleted_at IS NOT NULL
+	FROM "comments"
+	WHERE id = $1;`
+
+	// deleteComment leaves a tombstone in place of the comment, so its replies keep their parent
+	deleteComment = `
+	UPDATE "comments"
+	SET
+		body = '',
+		user_id = NULL,
+		guest_name = NULL,
+		deleted_at = CURRENT_TIMESTAMP,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND deleted_at IS NULL;`
+)
+
+func NewCommentRepository(database Database) CommentRepository {
+	return &commentRepo{db: database.GetDB()}
+}
+
+func (repo *commentRepo) CreateComment(ctx context.Context, comment *Comment) (*Comment, error) {
+	newComment := &Comment{}
+
+	row := repo.db.QueryRowxContext(ctx, createComment,
+		comment.ArticleID,
+		comment.ParentID,
+		comment.UserID,
+		comment.GuestName,
+		comment.Body,
+		comment.Status,
+	)
+
+	if err := row.StructScan(newComment); err != nil {
+		return nil, err
+	}
+
+	return newComment, nil
+}
+
+// GetComments returns a page of approved comment threads on the article, flattened depth first.
+// Paging applies to top level comments, replies are always returned with their thread.
+// Deleted comments are only returned when they have replies.
+func (repo *commentRepo) GetComments(ctx context.Context, articleID int, paging Paging) ([]Comment, PaginationData, error) {
+	comments := []Comment{}
+
+	err := repo.db.SelectContext(ctx, &comments, getComments, articleID, paging.Limit(), paging.Offset())
+	if err != nil {
+		return []Comment{}, PaginationData{}, err
+	}
+
+	var threadCount int
+	if err = repo.db.QueryRowContext(ctx, countComments, articleID).Scan(&threadCount); err != nil {
+		return []Comment{}, PaginationData{}, err
+	}
+
+	rootCount := 0
+	for _, comment := range comments {
+		if comment.Depth == 0 {
+			rootCount++
+		}
+	}
+
+	paginationData := PaginationData{}
+	paginationData.Build(paging, rootCount, threadCount)
+
+	return comments, paginationData, nil
+}
+
+func (repo *commentRepo) GetCommentsByStatus(ctx context.Context, status string, paging Paging) ([]Comment, PaginationData, error) {
+	comments := []Comment{}
+
+	err := repo.db.SelectContext(ctx, &comments, getCommentsByStatus, status, paging.Limit(), paging.Offset())
+	if err != nil {
+		return []Comment{}, PaginationData{}, err
+	}
+
+	var commentCount int
+	if err = repo.db.QueryRowContext(ctx, countCommentsByStatus, status).Scan(&commentCount); err != nil {
+		return []Comment{}, PaginationData{}, err
+	}
+
+	paginationData := PaginationData{}
+	paginationData.Build(paging, len(comments), commentCount)
+
+	return comments, paginationData, nil
+}
+
+func (repo *commentRepo) GetCommentByID(ctx context.Context, articleID, ID int) (*Comment, error) {
+	var comment Comment
+
+	err := repo.db.QueryRowxContext(ctx, getCommentByID, articleID, ID).StructScan(&comment)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+func (repo *commentRepo) UpdateCommentStatus(ctx context.Context, ID int, status string, moderatorID int) (*Comment, error) {
+	var comment Comment
+
+	err := repo.db.QueryRowxContext(ctx, updateCommentStatus, ID, status, moderatorID).StructScan(&comment)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repo.missingComment(ctx, ID)
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// missingComment tells why an update matched no comment, the comment is either deleted or doesn't exist
+func (repo *commentRepo) missingComment(ctx context.Context, ID int) error {
+	var deleted bool
+	if err := repo.db.GetContext(ctx, &deleted, commentDeleted, ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCommentNotFound
+		}
+		return err
+	}
+
+	if deleted {
+		return ErrCommentDeleted
+	}
+	return ErrCommentNotFound
+}
+
+// DeleteComment removes the comment's body and author but keeps its place in the thread
+func (repo *commentRepo) DeleteComment(ctx context.Context, ID int) error {
+	_, err := repo.db.ExecContext(ctx, deleteComment, ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComments(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	repo := NewCommentRepository(db)
+
+	user, err := NewUserRepository(db).CreateUser(context.Background(), &User{
+		Name:         "Jane Doe",
+		Email:        "jane@example.com",
+		PasswordHash: "not-a-real-hash",
+	})
+	require.NoError(t, err)
+
+	article, err := NewArticleRepository(db).CreateArticle(context.Background(), &Article{
+		Title:   "How to bake bread",
+		Content: "Just do it",
+	})
+	require.NoError(t, err)
+
+	var commentIDs, replyIDs []int
+	for _, body := range []string{"First!", "Great article", "Thanks for sharing"} {
+		comment, err := repo.CreateComment(context.Background(), &Comment{
+			ArticleID: article.ID,
+			UserID:    &user.ID,
+			Body:      body,
+			Status:    CommentStatusApproved,
+		})
+		require.NoError(t, err)
+		require.Equal(t, body, comment.Body)
+		require.Equal(t, user.Name, *comment.AuthorName)
+
+		commentIDs = append(commentIDs, comment.ID)
+	}
+
+	t.Run("list oldest first", func(t *testing.T) {
+		comments, paginationData, err := repo.GetComments(context.Background(), article.ID, Paging{Page: 1, PerPage: 2})
+		require.NoError(t, err)
+		require.Len(t, comments, 2)
+		require.Equal(t, commentIDs[0], comments[0].ID)
+		require.Equal(t, 3, paginationData.TotalItems)
+		require.Equal(t, 2, paginationData.TotalPages)
+	})
+
+	t.Run("find by id", func(t *testing.T) {
+		comment, err := repo.GetCommentByID(context.Background(), article.ID, commentIDs[1])
+		require.NoError(t, err)
+		require.Equal(t, "Great article", comment.Body)
+	})
+
+	t.Run("comment belongs to another article", func(t *testing.T) {
+		_, err := repo.GetCommentByID(context.Background(), 0, commentIDs[1])
+		require.ErrorIs(t, err, ErrCommentNotFound)
+	})
+
+	t.Run("replies follow their parent", func(t *testing.T) {
+		reply, err := repo.CreateComment(context.Background(), &Comment{
+			ArticleID: article.ID,
+			ParentID:  &commentIDs[0],
+			UserID:    &user.ID,
+			Body:      "Agreed",
+			Status:    CommentStatusApproved,
+		})
+		require.NoError(t, err)
+
+		nested, err := repo.CreateComment(context.Background(), &Comment{
+			ArticleID: article.ID,
+			ParentID:  &reply.ID,
+			UserID:    &user.ID,
+			Body:      "Me too",
+			Status:    CommentStatusApproved,
+		})
+		require.NoError(t, err)
+
+		comments, paginationData, err := repo.GetComments(context.Background(), article.ID, Paging{Page: 1, PerPage: 1})
+		require.NoError(t, err)
+		require.Len(t, comments, 3)
+		require.Equal(t, []int{commentIDs[0], reply.ID, nested.ID}, []int{comments[0].ID, comments[1].ID, comments[2].ID})
+		require.Equal(t, []int{0, 1, 2}, []int{comments[0].Depth, comments[1].Depth, comments[2].Depth})
+		require.Equal(t, 3, paginationData.TotalItems)
+
+		replyIDs = []int{reply.ID, nested.ID}
+	})
+
+	t.Run("moderation queue", func(t *testing.T) {
+		guestName := "Anonymous Coward"
+		pending, err := repo.CreateComment(context.Background(), &Comment{
+			ArticleID: article.ID,
+			GuestName: &guestName,
+			Body:      "Buy cheap watches",
+			Status:    CommentStatusPending,
+		})
+		require.NoError(t, err)
+		require.Equal(t, guestName, *pending.AuthorName)
+
+		queue, paginationData, err := repo.GetCommentsByStatus(context.Background(), CommentStatusPending, Paging{Page: 1, PerPage: 20})
+		require.NoError(t, err)
+		require.Len(t, queue, 1)
+		require.Equal(t, pending.ID, queue[0].ID)
+		require.Equal(t, 1, paginationData.TotalItems)
+
+		comments, _, err := repo.GetComments(context.Background(), article.ID, Paging{Page: 1, PerPage: 20})
+		require.NoError(t, err)
+		for _, comment := range comments {
+			require.NotEqual(t, pending.ID, comment.ID)
+		}
+
+		spam, err := repo.UpdateCommentStatus(context.Background(), pending.ID, CommentStatusSpam, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, CommentStatusSpam, spam.Status)
+
+		queue, _, err = repo.GetCommentsByStatus(context.Background(), CommentStatusPending, Paging{Page: 1, PerPage: 20})
+		require.NoError(t, err)
+		require.Empty(t, queue)
+
+		_, err = repo.UpdateCommentStatus(context.Background(), 0, CommentStatusApproved, user.ID)
+		require.ErrorIs(t, err, ErrCommentNotFound)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.DeleteComment(context.Background(), commentIDs[1]))
+
+		comment, err := repo.GetCommentByID(context.Background(), article.ID, commentIDs[1])
+		require.NoError(t, err)
+		require.True(t, comment.Deleted)
+		require.Empty(t, comment.Body)
+		require.Nil(t, comment.AuthorName)
+
+		// without replies there is nothing left to show
+		comments, _, err := repo.GetComments(context.Background(), article.ID, Paging{Page: 1, PerPage: 20})
+		require.NoError(t, err)
+		for _, comment := range comments {
+			require.NotEqual(t, commentIDs[1], comment.ID)
+		}
+	})
+
+	t.Run("deleting a parent keeps its replies", func(t *testing.T) {
+		require.NoError(t, repo.DeleteComment(context.Background(), commentIDs[0]))
+
+		comments, paginationData, err := repo.GetComments(context.Background(), article.ID, Paging{Page: 1, PerPage: 1})
+		require.NoError(t, err)
+		require.Len(t, comments, 3)
+		require.Equal(t, commentIDs[0], comments[0].ID)
+		require.True(t, comments[0].Deleted)
+		require.Nil(t, comments[0].AuthorName)
+		require.Equal(t, []string{"Agreed", "Me too"}, []string{comments[1].Body, comments[2].Body})
+		require.Equal(t, user.Name, *comments[1].AuthorName)
+		require.Equal(t, 2, paginationData.TotalItems)
+	})
+
+	t.Run("deleted comments can't be moderated", func(t *testing.T) {
+		_, err := repo.UpdateCommentStatus(context.Background(), commentIDs[0], CommentStatusRejected, user.ID)
+		require.ErrorIs(t, err, ErrCommentDeleted)
+	})
+
+	t.Run("threads of deleted comments are hidden", func(t *testing.T) {
+		for _, id := range replyIDs {
+			require.NoError(t, repo.DeleteComment(context.Background(), id))
+		}
+
+		comments, paginationData, err := repo.GetComments(context.Background(), article.ID, Paging{Page: 1, PerPage: 20})
+		require.NoError(t, err)
+		require.Len(t, comments, 1)
+		require.Equal(t, commentIDs[2], comments[0].ID)
+		require.Equal(t, 1, paginationData.TotalItems)
+	})
+
+	t.Run("comments are removed with the article", func(t *testing.T) {
+		require.NoError(t, NewArticleRepository(db).DeleteArticle(context.Background(), article.ID, article.Version))
+
+		comments, _, err := repo.GetComments(context.Background(), article.ID, Paging{Page: 1, PerPage: 20})
+		require.NoError(t, err)
+		require.Empty(t, comments)
+	})
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type mediaRepo struct {
+	db *sqlx.DB
+}
+
+var (
+	ErrMediaNotFound = errors.New("media not found")
+)
+
+const (
+	createMedia = `
+	INSERT INTO "media" (storage_key, filename, content_type, size, owner_id, article_id, attached)
+	VALUES ($1, $2, $3, $4, $5, $6, $6::int IS NOT NULL)
+	RETURNING *;`
+
+	getMediaByID = `
+	SELECT
+		*
+	FROM "media"
+	WHERE id = $1;`
+)
+
+func NewMediaRepository(database Database) MediaRepository {
+	return &mediaRepo{db: database.GetDB()}
+}
+
+func (repo *mediaRepo) CreateMedia(ctx context.Context, media *Media) (*Media, error) {
+	var createdMedia Media
+
+	err := repo.db.QueryRowxContext(ctx, createMedia,
+		media.Key,
+		media.Filename,
+		media.ContentType,
+		media.Size,
+		media.OwnerID,
+		media.ArticleID,
+	).StructScan(&createdMedia)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createdMedia, nil
+}
+
+func (repo *mediaRepo) GetMediaByID(ctx context.Context, ID int) (*Media, error) {
+	var media Media
+
+	err := repo.db.QueryRowxContext(ctx, getMediaByID, ID).StructScan(&media)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMediaNotFound
+		}
+		return nil, err
+	}
+
+	return &media, nil
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMedia(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	repo := NewMediaRepository(db)
+
+	user, err := NewUserRepository(db).CreateUser(context.Background(), &User{
+		Name:         "Jane Doe",
+		Email:        "jane@example.com",
+		PasswordHash: "not-a-real-hash",
+	})
+	require.NoError(t, err)
+
+	article, err := NewArticleRepository(db).CreateArticle(context.Background(), &Article{
+		Title:   "How to bake bread",
+		Content: "Just do it",
+	})
+	require.NoError(t, err)
+
+	media, err := repo.CreateMedia(context.Background(), &Media{
+		Key:         "media/0a1b2c.png",
+		Filename:    "bread.png",
+		ContentType: "image/png",
+		Size:        1024,
+		OwnerID:     &user.ID,
+		ArticleID:   &article.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "media/0a1b2c.png", media.Key)
+	require.Equal(t, article.ID, *media.ArticleID)
+
+	t.Run("get by id", func(t *testing.T) {
+		found, err := repo.GetMediaByID(context.Background(), media.ID)
+		require.NoError(t, err)
+		require.Equal(t, media.Filename, found.Filename)
+		require.Equal(t, media.Size, found.Size)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repo.GetMediaByID(context.Background(), media.ID+1)
+		require.ErrorIs(t, err, ErrMediaNotFound)
+	})
+
+	t.Run("outlives its article", func(t *testing.T) {
+		require.NoError(t, NewArticleRepository(db).DeleteArticle(context.Background(), article.ID, article.Version))
+
+		found, err := repo.GetMediaByID(context.Background(), media.ID)
+		require.NoError(t, err)
+		require.Nil(t, found.ArticleID)
+		require.True(t, found.Attached)
+	})
+}
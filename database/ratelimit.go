@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/ayo-awe/blogging_api/ratelimit"
+	"github.com/jmoiron/sqlx"
+)
+
+// PRUNE_RATE_LIMITS_EVERY is how many takes pass between deletions of full buckets
+const PRUNE_RATE_LIMITS_EVERY = 1000
+
+type rateLimitStore struct {
+	db      *sqlx.DB
+	logger  *slog.Logger
+	takes   atomic.Int64
+	pruning atomic.Bool
+	// window is the longest limit period seen, in nanoseconds
+	window atomic.Int64
+}
+
+const (
+	// lockRateLimit creates a full bucket or locks the existing one in a single statement,
+	// so a prune can't delete the bucket between creating and locking it
+	lockRateLimit = `
+	INSERT INTO "rate_limits" (key, tokens, updated_at, full_at)
+	VALUES ($1, $2, clock_timestamp(), clock_timestamp())
+	ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+	RETURNING
+		tokens,
+		updated_at;`
+
+	// getClock reads the database's clock, which every replica shares. It is read once the bucket is locked,
+	// so it is never earlier than the update made by the request that held the lock before.
+	getClock = `
+	SELECT clock_timestamp();`
+
+	updateRateLimit = `
+	UPDATE "rate_limits"
+	SET tokens = $2, updated_at = $3, full_at = $4
+	WHERE key = $1;`
+
+	pruneRateLimits = `
+	DELETE FROM "rate_limits"
+	WHERE full_at <= clock_timestamp() AND updated_at <= clock_timestamp() - make_interval(secs => $1);`
+)
+
+// NewRateLimitStore returns a rate limit store that shares buckets between every server using the database.
+// Refills are timed by the database's clock, so clock differences between the servers don't matter.
+func NewRateLimitStore(database Database, logger *slog.Logger) ratelimit.Store {
+	return &rateLimitStore{db: database.GetDB(), logger: logger}
+}
+
+func (store *rateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	var result ratelimit.Result
+
+	store.widenWindow(limit.Period)
+
+	err := withTx(ctx, store.db, func(tx *sqlx.Tx) error {
+		// the row lock makes concurrent requests for the same key take turns
+		var bucket ratelimit.Bucket
+		if err := tx.GetContext(ctx, &bucket, lockRateLimit, key); err != nil {
+			return err
+		}
+
+		var now time.Time
+		if err := tx.GetContext(ctx, &now, getClock); err != nil {
+			return err
+		}
+
+		result = bucket.Take(limit, now)
+
+		_, err := tx.ExecContext(ctx, updateRateLimit, key, bucket.Tokens, bucket.UpdatedAt, now.Add(result.Reset))
+		return err
+	})
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	// pruning is housekeeping, so it runs in the background and one at a time
+	if store.takes.Add(1)%PRUNE_RATE_LIMITS_EVERY == 0 && store.pruning.CompareAndSwap(false, true) {
+		go func() {
+			defer store.pruning.Store(false)
+
+			if err := store.prune(context.WithoutCancel(ctx)); err != nil {
+				store.logger.Error("RateLimitStore: " + err.Error())
+			}
+		}()
+	}
+
+	return result, nil
+}
+
+// widenWindow makes sure pruning keeps buckets for at least period after they were last used
+func (store *rateLimitStore) widenWindow(period time.Duration) {
+	for {
+		window := store.window.Load()
+		if int64(period) <= window || store.window.CompareAndSwap(window, int64(period)) {
+			return
+		}
+	}
+}
+
+// prune deletes full buckets that were not used for a whole window, they are recreated full when needed
+func (store *rateLimitStore) prune(ctx context.Context) error {
+	window := time.Duration(store.window.Load())
+	_, err := store.db.ExecContext(ctx, pruneRateLimits, window.Seconds())
+	return err
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/ayo-awe/blogging_api/ratelimit"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitStore(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	store := NewRateLimitStore(db, slog.Default())
+	limit := ratelimit.Limit{Requests: 2, Period: time.Hour}
+
+	for remaining := 1; remaining >= 0; remaining-- {
+		result, err := store.Take(context.Background(), "ip:192.0.2.1", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, remaining, result.Remaining)
+	}
+
+	result, err := store.Take(context.Background(), "ip:192.0.2.1", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Greater(t, result.RetryAfter, time.Duration(0))
+
+	t.Run("keys have their own buckets", func(t *testing.T) {
+		result, err := store.Take(context.Background(), "ip:192.0.2.2", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	})
+
+	countBuckets := func() int {
+		var count int
+		require.NoError(t, db.GetDB().Get(&count, `SELECT count(*) FROM "rate_limits"`))
+		return count
+	}
+
+	t.Run("recently used buckets are kept", func(t *testing.T) {
+		_, err := db.GetDB().Exec(`UPDATE "rate_limits" SET full_at = full_at - interval '2 hours'`)
+		require.NoError(t, err)
+		require.NoError(t, store.(*rateLimitStore).prune(context.Background()))
+		require.Equal(t, 2, countBuckets())
+	})
+
+	t.Run("full buckets are pruned", func(t *testing.T) {
+		_, err := db.GetDB().Exec(`UPDATE "rate_limits" SET updated_at = updated_at - interval '2 hours'`)
+		require.NoError(t, err)
+		require.NoError(t, store.(*rateLimitStore).prune(context.Background()))
+		require.Zero(t, countBuckets())
+	})
+}
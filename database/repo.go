@@ -3,18 +3,34 @@ package database
 import (
 	"context"
 	"database/sql/driver"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"math"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/ayo-awe/blogging_api/render"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/lib/pq"
+)
+
+const (
+	TagsModeAny = "any"
+	TagsModeAll = "all"
 )
 
 type ArticleFilter struct {
 	Tags Tags
+	// TagsMode is TagsModeAll to match articles with every tag in Tags, any of them otherwise
+	TagsMode    string
+	ExcludeTags Tags
+	Status      string
+	AuthorID    *int
+	// Query is a web search style full-text query over title and content
+	Query string
 }
 
 type PaginationData struct {
@@ -46,6 +62,48 @@ func (p Paging) Offset() int {
 	return (p.Page - 1) * p.PerPage
 }
 
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks a position in a keyset ordered by (sort time, id), newest first
+type Cursor struct {
+	SortAt time.Time `json:"t"`
+	ID     int       `json:"id"`
+	// Before selects the items preceding the cursor instead of the ones following it
+	Before bool `json:"b,omitempty"`
+}
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(raw string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(b, &cursor); err != nil || cursor.SortAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+type CursorPaging struct {
+	// Cursor is nil when requesting the first page
+	Cursor *Cursor
+	Limit  int
+}
+
+type CursorPaginationData struct {
+	NextCursor *string `json:"next_cursor" example:"eyJ0IjoiMjAyNC0wNi0yM1QyMToyMToxOS4wMDE5OVoiLCJpZCI6NDJ9"`
+	PrevCursor *string `json:"prev_cursor" example:"eyJ0IjoiMjAyNC0wNi0yM1QyMToyMToxOS4wMDE5OVoiLCJpZCI6NDMsImIiOnRydWV9"`
+	ItemCount  int     `json:"item_count" example:"25"`
+	Limit      int     `json:"limit" example:"25"`
+}
+
 type Tags []string
 
 func (t Tags) Value() (driver.Value, error) {
@@ -70,13 +128,104 @@ func (t *Tags) Scan(value interface{}) error {
 	return json.Unmarshal(b, t)
 }
 
+const (
+	ArticleStatusDraft     = "draft"
+	ArticleStatusPublished = "published"
+	ArticleStatusArchived  = "archived"
+)
+
+const (
+	ContentFormatMarkdown = "markdown"
+	ContentFormatPlain    = "plain"
+)
+
+// Article is a blog post. ContentHTML caches Content rendered to sanitized HTML, the API only sends it when asked to.
 type Article struct {
-	ID          int       `json:"id" db:"id" example:"1"`
-	Title       string    `json:"title" db:"title" example:"I love Golang"`
-	Content     string    `json:"content" db:"content" example:"lorem ipsum lorem ipsum"`
-	Tags        Tags      `json:"tags" db:"tags" example:"golang,go,tech"`
-	PublishedAt time.Time `json:"published_at" db:"published_at" example:"2024-06-23T22:21:19.00199+01:00"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at" example:"2024-06-23T22:21:19.00199+01:00"`
+	ID            int     `json:"id" db:"id" example:"1"`
+	Title         string  `json:"title" db:"title" example:"I love Golang"`
+	Slug          string  `json:"slug" db:"slug" example:"i-love-golang"`
+	Content       string  `json:"content" db:"content" example:"lorem ipsum lorem ipsum"`
+	ContentFormat string  `json:"content_format" db:"content_format" example:"markdown"`
+	ContentHTML   *string `json:"content_html,omitempty" db:"content_html" example:"<p>lorem ipsum lorem ipsum</p>"`
+	Tags          Tags    `json:"tags" db:"tags" example:"golang,go,tech"`
+	AuthorID      *int    `json:"author_id" db:"author_id" example:"1"`
+	AuthorName    *string `json:"author_name" db:"author_name" example:"Ayo Awe"`
+	Status        string  `json:"status" db:"status" example:"published"`
+	Revision      int     `json:"revision" db:"revision" example:"3"`
+	// Version is bumped by every change to the article, updates and deletes must name the version they expect
+	Version     int        `json:"version" db:"version" example:"5"`
+	PublishAt   *time.Time `json:"publish_at" db:"publish_at" example:"2024-06-25T09:00:00Z"`
+	PublishedAt *time.Time `json:"published_at" db:"published_at" example:"2024-06-23T22:21:19.00199+01:00"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at" example:"2024-06-23T22:21:19.00199+01:00"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at" example:"2024-06-23T22:21:19.00199+01:00"`
+	// Snippet holds highlighted matches when articles are searched
+	Snippet *string `json:"snippet,omitempty" db:"snippet" example:"I <mark>love</mark> Golang"`
+}
+
+// SitemapEntry is the part of a published article listed in sitemaps
+type SitemapEntry struct {
+	Slug      string    `db:"slug"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// SitemapPage is a page of the sitemap index, page n lists the published articles with ids from n*pageSize
+// up to (n+1)*pageSize
+type SitemapPage struct {
+	Page      int       `db:"page"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// ArticleRevision is an immutable snapshot of an article's editable fields
+type ArticleRevision struct {
+	ID         int       `json:"id" db:"id" example:"7"`
+	ArticleID  int       `json:"article_id" db:"article_id" example:"1"`
+	Revision   int       `json:"revision" db:"revision" example:"3"`
+	Title      string    `json:"title" db:"title" example:"I love Golang"`
+	Content    string    `json:"content" db:"content" example:"lorem ipsum lorem ipsum"`
+	Tags       Tags      `json:"tags" db:"tags" example:"golang,go,tech"`
+	EditorID   *int      `json:"editor_id" db:"editor_id" example:"1"`
+	EditorName *string   `json:"editor_name" db:"editor_name" example:"Ayo Awe"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at" example:"2024-06-23T22:21:19.00199+01:00"`
+}
+
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+)
+
+type Comment struct {
+	ID        int  `json:"id" db:"id" example:"1"`
+	ArticleID int  `json:"article_id" db:"article_id" example:"1"`
+	ParentID  *int `json:"parent_id" db:"parent_id" example:"1"`
+	UserID    *int `json:"user_id" db:"user_id" example:"1"`
+	// GuestName is the name given by an anonymous commenter, it is returned as AuthorName
+	GuestName  *string   `json:"-" db:"-"`
+	AuthorName *string   `json:"author_name" db:"author_name" example:"Ayo Awe"`
+	Body       string    `json:"body" db:"body" example:"Great article!"`
+	Status     string    `json:"status" db:"status" example:"approved"`
+	Depth      int       `json:"depth" db:"depth" example:"0"`
+	Deleted    bool      `json:"deleted" db:"deleted" example:"false"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at" example:"2024-06-23T22:21:19.00199+01:00"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at" example:"2024-06-23T22:21:19.00199+01:00"`
+}
+
+// sortAt is the time articles are ordered by in listings
+func (a *Article) sortAt() time.Time {
+	if a.PublishedAt != nil {
+		return *a.PublishedAt
+	}
+	return a.CreatedAt
+}
+
+// RenderContent renders Content to sanitized HTML according to ContentFormat, defaulting to Markdown
+func (a *Article) RenderContent() (string, error) {
+	if a.ContentFormat == ContentFormatPlain {
+		return render.PlainText(a.Content), nil
+	}
+
+	return render.Markdown(a.Content)
 }
 
 func (a *Article) Validate() error {
@@ -84,6 +233,7 @@ func (a *Article) Validate() error {
 	return validation.ValidateStruct(a,
 		validation.Field(&a.Title, validation.Required, validation.Length(5, 255)),
 		validation.Field(&a.Content, validation.Required, validation.Length(5, 0)),
+		validation.Field(&a.ContentFormat, validation.In(ContentFormatMarkdown, ContentFormatPlain)),
 		validation.Field(&a.Tags, validation.Each(validation.Length(2, 0), is.LowerCase)),
 	)
 }
@@ -91,6 +241,7 @@ func (a *Article) Validate() error {
 func (a *Article) clean() {
 	a.Title = strings.TrimSpace(a.Title)
 	a.Content = strings.TrimSpace(a.Content)
+	a.ContentFormat = strings.ToLower(strings.TrimSpace(a.ContentFormat))
 
 	for i, tag := range a.Tags {
 		trimmed := strings.TrimSpace(tag)
@@ -98,10 +249,113 @@ func (a *Article) clean() {
 	}
 }
 
+const (
+	TagSortPopular = "popular"
+	TagSortName    = "name"
+)
+
+// Tag holds the metadata of a tag used in articles' tags. ArticleCount only counts published articles.
+type Tag struct {
+	Name         string    `json:"name" db:"name" example:"artificial intelligence"`
+	Slug         string    `json:"slug" db:"slug" example:"artificial-intelligence"`
+	Description  string    `json:"description" db:"description" example:"Machine learning, neural networks and everything in between"`
+	ArticleCount int       `json:"article_count" db:"article_count" example:"12"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" example:"2024-06-23T22:21:19.00199+01:00"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at" example:"2024-06-23T22:21:19.00199+01:00"`
+}
+
+// Media is an uploaded file, its content lives in storage under Key
+type Media struct {
+	ID          int       `json:"id" db:"id" example:"1"`
+	Key         string    `json:"-" db:"storage_key"`
+	Filename    string    `json:"filename" db:"filename" example:"diagram.png"`
+	ContentType string    `json:"content_type" db:"content_type" example:"image/png"`
+	Size        int64     `json:"size" db:"size" example:"48213"`
+	OwnerID     *int      `json:"owner_id" db:"owner_id" example:"1"`
+	ArticleID   *int      `json:"article_id" db:"article_id" example:"1"`
+	Attached    bool      `json:"-" db:"attached"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at" example:"2024-06-23T22:21:19.00199+01:00"`
+}
+
+const (
+	RoleReader = "reader"
+	RoleAuthor = "author"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Permission names as seeded in the role_permissions table
+const (
+	PermArticlesCreate     = "articles:create"
+	PermArticlesUpdate     = "articles:update"
+	PermArticlesDelete     = "articles:delete"
+	PermArticlesUpdateAny  = "articles:update:any"
+	PermArticlesDeleteAny  = "articles:delete:any"
+	PermArticlesPublish    = "articles:publish"
+	PermArticlesPublishAny = "articles:publish:any"
+	PermUsersManage        = "users:manage"
+	PermCommentsCreate     = "comments:create"
+	PermCommentsModerate   = "comments:moderate"
+	PermTagsManage         = "tags:manage"
+	PermMediaUpload        = "media:upload"
+)
+
+type User struct {
+	ID           int            `json:"id" db:"id" example:"1"`
+	Name         string         `json:"name" db:"name" example:"Ayo Awe"`
+	Email        string         `json:"email" db:"email" example:"ayo@example.com"`
+	PasswordHash string         `json:"-" db:"password_hash"`
+	Role         string         `json:"role" db:"role" example:"author"`
+	Permissions  pq.StringArray `json:"permissions" db:"permissions" swaggertype:"array,string" example:"articles:create,articles:update"`
+	CreatedAt    time.Time      `json:"created_at" db:"created_at" example:"2024-06-23T22:21:19.00199+01:00"`
+	UpdatedAt    time.Time      `json:"updated_at" db:"updated_at" example:"2024-06-23T22:21:19.00199+01:00"`
+}
+
+func (u *User) HasPermission(permission string) bool {
+	return slices.Contains(u.Permissions, permission)
+}
+
 type ArticleRepository interface {
 	GetArticles(ctx context.Context, filter ArticleFilter, pageable Paging) ([]Article, PaginationData, error)
+	GetArticlesByCursor(ctx context.Context, filter ArticleFilter, paging CursorPaging) ([]Article, CursorPaginationData, error)
 	GetArticleByID(ctx context.Context, ID int) (*Article, error)
+	GetArticleBySlug(ctx context.Context, slug string) (*Article, error)
 	CreateArticle(ctx context.Context, article *Article) (*Article, error)
-	UpdateArticle(ctx context.Context, article *Article) (*Article, error)
-	DeleteArticle(ctx context.Context, ID int) error
+	UpdateArticle(ctx context.Context, article *Article, editorID *int) (*Article, error)
+	UpdateArticleStatus(ctx context.Context, ID int, status string) (*Article, error)
+	PublishDueArticles(ctx context.Context, limit int) ([]int, error)
+	GetSitemapPages(ctx context.Context, pageSize int) ([]SitemapPage, error)
+	EachSitemapEntry(ctx context.Context, page, pageSize int, fn func(SitemapEntry) error) error
+	DeleteArticle(ctx context.Context, ID, version int) error
+	GetArticleRevisions(ctx context.Context, articleID int, paging Paging) ([]ArticleRevision, PaginationData, error)
+	GetArticleRevision(ctx context.Context, articleID, revision int) (*ArticleRevision, error)
+}
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, user *User) (*User, error)
+	GetUsers(ctx context.Context, paging Paging) ([]User, PaginationData, error)
+	GetUserByID(ctx context.Context, ID int) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	UpdateUserRole(ctx context.Context, ID int, role string) (*User, error)
+}
+
+type CommentRepository interface {
+	CreateComment(ctx context.Context, comment *Comment) (*Comment, error)
+	GetComments(ctx context.Context, articleID int, paging Paging) ([]Comment, PaginationData, error)
+	GetCommentsByStatus(ctx context.Context, status string, paging Paging) ([]Comment, PaginationData, error)
+	GetCommentByID(ctx context.Context, articleID, ID int) (*Comment, error)
+	UpdateCommentStatus(ctx context.Context, ID int, status string, moderatorID int) (*Comment, error)
+	DeleteComment(ctx context.Context, ID int) error
+}
+
+type TagRepository interface {
+	GetTags(ctx context.Context, sort string, paging Paging) ([]Tag, PaginationData, error)
+	GetTagBySlug(ctx context.Context, slug string) (*Tag, error)
+	UpdateTag(ctx context.Context, tag *Tag) (*Tag, error)
+	MergeTags(ctx context.Context, slugs []string, into string, editorID *int) (*Tag, int, error)
+}
+
+type MediaRepository interface {
+	CreateMedia(ctx context.Context, media *Media) (*Media, error)
+	GetMediaByID(ctx context.Context, ID int) (*Media, error)
 }
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		cursor := Cursor{
+			SortAt: time.Date(2024, 6, 23, 21, 21, 19, 1990000, time.UTC),
+			ID:     42,
+			Before: true,
+		}
+
+		decoded, err := DecodeCursor(cursor.Encode())
+		require.NoError(t, err)
+		require.True(t, cursor.SortAt.Equal(decoded.SortAt))
+		require.Equal(t, cursor.ID, decoded.ID)
+		require.Equal(t, cursor.Before, decoded.Before)
+	})
+
+	invalidCursors := []string{"not base64!", "bm90IGpzb24", "e30"}
+	for _, raw := range invalidCursors {
+		t.Run("invalid "+raw, func(t *testing.T) {
+			_, err := DecodeCursor(raw)
+			require.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
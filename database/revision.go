@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrRevisionNotFound = errors.New("revision not found")
+)
+
+const (
+	insertArticleRevision = `
+	INSERT INTO "article_revisions" (article_id, revision, title, content, tags, editor_id, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7);`
+
+	getArticleRevisions = `
+	SELECT
+		r.id,
+		r.article_id,
+		r.revision,
+		r.title,
+		r.content,
+		r.tags,
+		r.editor_id,
+		u.name AS editor_name,
+		r.created_at
+	FROM "article_revisions" r
+	LEFT JOIN "users" u ON u.id = r.editor_id
+	WHERE r.article_id = $1
+	ORDER BY r.revision DESC
+	LIMIT $2
+	OFFSET $3;`
+
+	countArticleRevisions = `
+	SELECT
+		count(*)
+	FROM "article_revisions"
+	WHERE article_id = $1;`
+
+	getArticleRevision = `
+	SELECT
+		r.id,
+		r.article_id,
+		r.revision,
+		r.title,
+		r.content,
+		r.tags,
+		r.editor_id,
+		u.name AS editor_name,
+		r.created_at
+	FROM "article_revisions" r
+	LEFT JOIN "users" u ON u.id = r.editor_id
+	WHERE r.article_id = $1 AND r.revision = $2;`
+)
+
+// insertRevision snapshots the article's current title, content and tags
+func insertRevision(ctx context.Context, tx *sqlx.Tx, article *Article, editorID *int) error {
+	_, err := tx.ExecContext(ctx, insertArticleRevision,
+		article.ID,
+		article.Revision,
+		article.Title,
+		article.Content,
+		article.Tags,
+		editorID,
+		article.UpdatedAt,
+	)
+
+	return err
+}
+
+func (repo *articleRepo) GetArticleRevisions(ctx context.Context, articleID int, paging Paging) ([]ArticleRevision, PaginationData, error) {
+	revisions := []ArticleRevision{}
+
+	err := repo.db.SelectContext(ctx, &revisions, getArticleRevisions, articleID, paging.Limit(), paging.Offset())
+	if err != nil {
+		return []ArticleRevision{}, PaginationData{}, err
+	}
+
+	var revisionCount int
+	if err = repo.db.QueryRowContext(ctx, countArticleRevisions, articleID).Scan(&revisionCount); err != nil {
+		return []ArticleRevision{}, PaginationData{}, err
+	}
+
+	paginationData := PaginationData{}
+	paginationData.Build(paging, len(revisions), revisionCount)
+
+	return revisions, paginationData, nil
+}
+
+func (repo *articleRepo) GetArticleRevision(ctx context.Context, articleID, revision int) (*ArticleRevision, error) {
+	var articleRevision ArticleRevision
+
+	err := repo.db.QueryRowxContext(ctx, getArticleRevision, articleID, revision).StructScan(&articleRevision)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+
+	return &articleRevision, nil
+}
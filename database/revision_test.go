@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleRevisions(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	repo := NewArticleRepository(db)
+
+	article, err := repo.CreateArticle(context.Background(), &Article{
+		Title:   "First title",
+		Content: "First content",
+		Tags:    Tags{"first"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, article.Revision)
+
+	article.Title = "Second title"
+	article.Content = "Second content"
+	article.Tags = Tags{"second"}
+
+	updatedArticle, err := repo.UpdateArticle(context.Background(), article, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, updatedArticle.Revision)
+
+	t.Run("list revisions newest first", func(t *testing.T) {
+		revisions, paginationData, err := repo.GetArticleRevisions(context.Background(), article.ID, Paging{Page: 1, PerPage: 20})
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		require.Equal(t, 2, paginationData.TotalItems)
+		require.Equal(t, 2, revisions[0].Revision)
+		require.Equal(t, 1, revisions[1].Revision)
+	})
+
+	t.Run("previous revision is preserved", func(t *testing.T) {
+		revision, err := repo.GetArticleRevision(context.Background(), article.ID, 1)
+		require.NoError(t, err)
+		require.Equal(t, "First title", revision.Title)
+		require.Equal(t, "First content", revision.Content)
+		require.Equal(t, Tags{"first"}, revision.Tags)
+	})
+
+	t.Run("revision not found", func(t *testing.T) {
+		_, err := repo.GetArticleRevision(context.Background(), article.ID, 3)
+		require.ErrorIs(t, err, ErrRevisionNotFound)
+	})
+
+	t.Run("revisions are removed with the article", func(t *testing.T) {
+		require.NoError(t, repo.DeleteArticle(context.Background(), article.ID, updatedArticle.Version))
+
+		revisions, _, err := repo.GetArticleRevisions(context.Background(), article.ID, Paging{Page: 1, PerPage: 20})
+		require.NoError(t, err)
+		require.Empty(t, revisions)
+	})
+}
@@ -0,0 +1,299 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/ayo-awe/blogging_api/slug"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type tagRepo struct {
+	db *sqlx.DB
+}
+
+// MAX_SLUG_ATTEMPTS bounds how often a slug is picked again after a concurrent transaction took it
+const MAX_SLUG_ATTEMPTS = 5
+
+var (
+	ErrTagNotFound  = errors.New("tag not found")
+	ErrSlugConflict = errors.New("no free slug after repeated conflicts")
+)
+
+const (
+	// tagColumns are the columns scanned into a Tag.
+	// Queries using it must alias the tag row as t, join published articles as a and group by t.name.
+	tagColumns = `
+		t.name,
+		t.slug,
+		t.description,
+		count(a.id) AS article_count,
+		t.created_at,
+		t.updated_at`
+
+	// getTags only lists tags used by at least one published article
+	getTags = `
+	SELECT` + tagColumns + `
+	FROM "tags" t
+	JOIN "articles" a ON a.tags ? t.name AND a.status = 'published'
+	GROUP BY t.name
+	ORDER BY
+		CASE WHEN $1 = 'popular' THEN count(a.id) END DESC,
+		t.name
+	LIMIT $2
+	OFFSET $3;`
+
+	countTags = `
+	SELECT
+		count(DISTINCT t.name)
+	FROM "tags" t
+	JOIN "articles" a ON a.tags ? t.name AND a.status = 'published';`
+
+	getTagBySlug = `
+	SELECT` + tagColumns + `
+	FROM "tags" t
+	LEFT JOIN "articles" a ON a.tags ? t.name AND a.status = 'published'
+	WHERE t.slug = $1
+	GROUP BY t.name;`
+
+	updateTag = `
+	WITH updated AS (
+		UPDATE "tags"
+		SET
+			description = $2,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE slug = $1
+		RETURNING *
+	)
+	SELECT` + tagColumns + `
+	FROM updated t
+	LEFT JOIN "articles" a ON a.tags ? t.name AND a.status = 'published'
+	GROUP BY t.name, t.slug, t.description, t.created_at, t.updated_at;`
+
+	getTagByName = `
+	SELECT` + tagColumns + `
+	FROM "tags" t
+	LEFT JOIN "articles" a ON a.tags ? t.name AND a.status = 'published'
+	WHERE t.name = $1
+	GROUP BY t.name;`
+
+	lockTagsBySlug = `
+	SELECT name, slug, description
+	FROM "tags"
+	WHERE slug = ANY($1)
+	ORDER BY name
+	FOR UPDATE;`
+
+	// replaceArticleTags swaps the tags named in $1 for $2 in every article using them,
+	// keeping the first position of each tag and dropping the duplicates this creates.
+	// It returns what insertRevision needs to record the change.
+	replaceArticleTags = `
+	UPDATE "articles" a
+	SET
+		tags = (
+			SELECT COALESCE(jsonb_agg(tag ORDER BY position), '[]'::jsonb)
+			FROM (
+				SELECT
+					CASE WHEN t.tag = ANY($1) THEN $2 ELSE t.tag END AS tag,
+					min(t.position) AS position
+				FROM jsonb_array_elements_text(a.tags) WITH ORDINALITY AS t(tag, position)
+				GROUP BY 1
+			) deduplicated
+		),
+		version = a.version + 1,
+		revision = a.revision + 1,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE a.tags ?| $1
+	RETURNING a.id, a.revision, a.title, a.content, a.tags, a.updated_at;`
+
+	deleteTags = `
+	DELETE FROM "tags"
+	WHERE name = ANY($1) AND name <> $2;`
+
+	describeTagIfEmpty = `
+	UPDATE "tags"
+	SET description = $2
+	WHERE name = $1 AND description = '';`
+
+	tagExists = `
+	SELECT EXISTS (SELECT 1 FROM "tags" WHERE name = $1);`
+
+	// getSimilarSlugs finds the slugs a new tag slugified to $1 could collide with
+	getSimilarSlugs = `
+	SELECT slug
+	FROM "tags"
+	WHERE slug = $1 OR slug LIKE $1 || '-%';`
+
+	// insertTag waits for transactions inserting the same name or slug and does nothing if they commit
+	insertTag = `
+	INSERT INTO "tags" (name, slug)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING;`
+)
+
+func NewTagRepository(database Database) TagRepository {
+	return &tagRepo{db: database.GetDB()}
+}
+
+func (repo *tagRepo) GetTags(ctx context.Context, sort string, paging Paging) ([]Tag, PaginationData, error) {
+	tags := []Tag{}
+
+	err := repo.db.SelectContext(ctx, &tags, getTags, sort, paging.Limit(), paging.Offset())
+	if err != nil {
+		return []Tag{}, PaginationData{}, err
+	}
+
+	var tagCount int
+	if err = repo.db.QueryRowContext(ctx, countTags).Scan(&tagCount); err != nil {
+		return []Tag{}, PaginationData{}, err
+	}
+
+	paginationData := PaginationData{}
+	paginationData.Build(paging, len(tags), tagCount)
+
+	return tags, paginationData, nil
+}
+
+func (repo *tagRepo) GetTagBySlug(ctx context.Context, slug string) (*Tag, error) {
+	var tag Tag
+
+	err := repo.db.QueryRowxContext(ctx, getTagBySlug, slug).StructScan(&tag)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (repo *tagRepo) UpdateTag(ctx context.Context, tag *Tag) (*Tag, error) {
+	var updatedTag Tag
+
+	err := repo.db.QueryRowxContext(ctx, updateTag, tag.Slug, tag.Description).StructScan(&updatedTag)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+
+	return &updatedTag, nil
+}
+
+// MergeTags replaces the tags with the given slugs by a tag named into in every article, in a single transaction.
+// into may be one of the merged tags or a new name, in which case it inherits the first non-empty description.
+// Each rewritten article gets a revision by editorID. It returns the resulting tag and the number of articles rewritten.
+func (repo *tagRepo) MergeTags(ctx context.Context, slugs []string, into string, editorID *int) (*Tag, int, error) {
+	var tag Tag
+	var articles []Article
+
+	slugs = slices.Clone(slugs)
+	slices.Sort(slugs)
+	slugs = slices.Compact(slugs)
+
+	err := withTx(ctx, repo.db, func(tx *sqlx.Tx) error {
+		var merged []Tag
+		if err := tx.SelectContext(ctx, &merged, lockTagsBySlug, pq.Array(slugs)); err != nil {
+			return err
+		}
+
+		if len(merged) != len(slugs) {
+			return ErrTagNotFound
+		}
+
+		names := make([]string, len(merged))
+		description := ""
+		for i, t := range merged {
+			names[i] = t.Name
+			if description == "" {
+				description = t.Description
+			}
+		}
+
+		if err := tx.SelectContext(ctx, &articles, replaceArticleTags, pq.Array(names), into); err != nil {
+			return err
+		}
+
+		for _, article := range articles {
+			if err := insertRevision(ctx, tx, &article, editorID); err != nil {
+				return err
+			}
+		}
+
+		// the old rows go first so a renamed tag can take over its previous slug
+		if _, err := tx.ExecContext(ctx, deleteTags, pq.Array(names), into); err != nil {
+			return err
+		}
+
+		if err := syncTags(ctx, tx, Tags{into}); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, describeTagIfEmpty, into, description); err != nil {
+			return err
+		}
+
+		return tx.QueryRowxContext(ctx, getTagByName, into).StructScan(&tag)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &tag, len(articles), nil
+}
+
+// syncTags gives every tag in tags a row in the tags table, so new tags can be listed and described.
+// Names are inserted in order so concurrent transactions adding the same tags don't deadlock.
+func syncTags(ctx context.Context, tx *sqlx.Tx, tags Tags) error {
+	names := slices.Clone(tags)
+	slices.Sort(names)
+
+	for _, name := range slices.Compact(names) {
+		if err := syncTag(ctx, tx, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncTag inserts a row for the tag unless it has one. When another transaction takes the name
+// or the slug first, it looks again, finding the tag or picking the next free slug.
+func syncTag(ctx context.Context, tx *sqlx.Tx, name string) error {
+	base := slug.Make(name)
+	if base == "" {
+		base = "tag"
+	}
+
+	for attempt := 0; attempt < MAX_SLUG_ATTEMPTS; attempt++ {
+		var exists bool
+		if err := tx.GetContext(ctx, &exists, tagExists, name); err != nil {
+			return err
+		}
+
+		if exists {
+			return nil
+		}
+
+		var taken []string
+		if err := tx.SelectContext(ctx, &taken, getSimilarSlugs, base); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, insertTag, name, slug.Unique(base, taken))
+		if err != nil {
+			return err
+		}
+
+		if inserted, err := result.RowsAffected(); err != nil || inserted == 1 {
+			return err
+		}
+	}
+
+	return ErrSlugConflict
+}
@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	articles := NewArticleRepository(db)
+	repo := NewTagRepository(db)
+
+	for _, tags := range []Tags{{"go", "web development"}, {"go"}, {"go", "c++"}} {
+		article, err := articles.CreateArticle(context.Background(), &Article{
+			Title:   "How to bake bread",
+			Content: "Just do it",
+			Tags:    tags,
+		})
+		require.NoError(t, err)
+
+		_, err = articles.UpdateArticleStatus(context.Background(), article.ID, ArticleStatusPublished)
+		require.NoError(t, err)
+	}
+
+	_, err := articles.CreateArticle(context.Background(), &Article{
+		Title:   "Unfinished draft",
+		Content: "Not yet",
+		Tags:    Tags{"web development", "drafts"},
+	})
+	require.NoError(t, err)
+
+	t.Run("most popular first", func(t *testing.T) {
+		tags, paginationData, err := repo.GetTags(context.Background(), TagSortPopular, Paging{Page: 1, PerPage: 2})
+		require.NoError(t, err)
+		require.Len(t, tags, 2)
+		require.Equal(t, "go", tags[0].Name)
+		require.Equal(t, 3, tags[0].ArticleCount)
+		require.Equal(t, 3, paginationData.TotalItems)
+	})
+
+	t.Run("by name", func(t *testing.T) {
+		tags, _, err := repo.GetTags(context.Background(), TagSortName, Paging{Page: 1, PerPage: 20})
+		require.NoError(t, err)
+		require.Len(t, tags, 3)
+		require.Equal(t, []string{"c++", "go", "web development"}, []string{tags[0].Name, tags[1].Name, tags[2].Name})
+	})
+
+	t.Run("find by slug", func(t *testing.T) {
+		tag, err := repo.GetTagBySlug(context.Background(), "web-development")
+		require.NoError(t, err)
+		require.Equal(t, "web development", tag.Name)
+		require.Equal(t, 1, tag.ArticleCount)
+
+		_, err = repo.GetTagBySlug(context.Background(), "rust")
+		require.ErrorIs(t, err, ErrTagNotFound)
+	})
+
+	t.Run("colliding slugs get a suffix", func(t *testing.T) {
+		_, err := articles.CreateArticle(context.Background(), &Article{
+			Title:   "Cee",
+			Content: "Plain old C",
+			Tags:    Tags{"c"},
+		})
+		require.NoError(t, err)
+
+		tag, err := repo.GetTagBySlug(context.Background(), "c-2")
+		require.NoError(t, err)
+		require.Equal(t, "c", tag.Name)
+	})
+
+	t.Run("concurrent new tags", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+
+		for i := 0; i < cap(errs); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				_, err := articles.CreateArticle(context.Background(), &Article{
+					Title:   fmt.Sprintf("Machine learning, part %d", i+1),
+					Content: "Gradients all the way down",
+					Tags:    Tags{"machine-learning", "machine learning"},
+				})
+				errs <- err
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		first, err := repo.GetTagBySlug(context.Background(), "machine-learning")
+		require.NoError(t, err)
+
+		second, err := repo.GetTagBySlug(context.Background(), "machine-learning-2")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"machine-learning", "machine learning"}, []string{first.Name, second.Name})
+	})
+
+	t.Run("describe", func(t *testing.T) {
+		tag, err := repo.UpdateTag(context.Background(), &Tag{Slug: "go", Description: "The Go programming language"})
+		require.NoError(t, err)
+		require.Equal(t, "The Go programming language", tag.Description)
+		require.Equal(t, 3, tag.ArticleCount)
+
+		_, err = repo.UpdateTag(context.Background(), &Tag{Slug: "rust"})
+		require.ErrorIs(t, err, ErrTagNotFound)
+	})
+}
+
+func TestMergeTags(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	articles := NewArticleRepository(db)
+	repo := NewTagRepository(db)
+
+	editor, err := NewUserRepository(db).CreateUser(context.Background(), &User{
+		Name:         "Jane Doe",
+		Email:        "jane@example.com",
+		PasswordHash: "not-a-real-hash",
+	})
+	require.NoError(t, err)
+
+	var articleIDs []int
+	for _, tags := range []Tags{{"golang", "go", "web"}, {"go-lang"}, {"web"}} {
+		article, err := articles.CreateArticle(context.Background(), &Article{
+			Title:   "How to bake bread",
+			Content: "Just do it",
+			Tags:    tags,
+		})
+		require.NoError(t, err)
+
+		articleIDs = append(articleIDs, article.ID)
+	}
+
+	_, err = repo.UpdateTag(context.Background(), &Tag{Slug: "golang", Description: "The Go programming language"})
+	require.NoError(t, err)
+
+	t.Run("merge into an existing tag", func(t *testing.T) {
+		tag, articleCount, err := repo.MergeTags(context.Background(), []string{"golang", "go-lang"}, "go", &editor.ID)
+		require.NoError(t, err)
+		require.Equal(t, "go", tag.Name)
+		require.Equal(t, "The Go programming language", tag.Description)
+		require.Equal(t, 2, articleCount)
+
+		article, err := articles.GetArticleByID(context.Background(), articleIDs[0])
+		require.NoError(t, err)
+		require.Equal(t, Tags{"go", "web"}, article.Tags)
+
+		article, err = articles.GetArticleByID(context.Background(), articleIDs[1])
+		require.NoError(t, err)
+		require.Equal(t, Tags{"go"}, article.Tags)
+
+		_, err = repo.GetTagBySlug(context.Background(), "golang")
+		require.ErrorIs(t, err, ErrTagNotFound)
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		tag, articleCount, err := repo.MergeTags(context.Background(), []string{"web"}, "web development", &editor.ID)
+		require.NoError(t, err)
+		require.Equal(t, "web-development", tag.Slug)
+		require.Equal(t, 2, articleCount)
+
+		article, err := articles.GetArticleByID(context.Background(), articleIDs[2])
+		require.NoError(t, err)
+		require.Equal(t, Tags{"web development"}, article.Tags)
+	})
+
+	t.Run("records a revision of each article", func(t *testing.T) {
+		article, err := articles.GetArticleByID(context.Background(), articleIDs[0])
+		require.NoError(t, err)
+		require.Equal(t, 3, article.Revision)
+
+		revision, err := articles.GetArticleRevision(context.Background(), article.ID, article.Revision)
+		require.NoError(t, err)
+		require.Equal(t, article.Tags, revision.Tags)
+		require.Equal(t, editor.ID, *revision.EditorID)
+
+		previous, err := articles.GetArticleRevision(context.Background(), article.ID, article.Revision-1)
+		require.NoError(t, err)
+		require.Equal(t, Tags{"go", "web"}, previous.Tags)
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		_, _, err := repo.MergeTags(context.Background(), []string{"go", "rust"}, "systems", &editor.ID)
+		require.ErrorIs(t, err, ErrTagNotFound)
+
+		article, err := articles.GetArticleByID(context.Background(), articleIDs[1])
+		require.NoError(t, err)
+		require.Equal(t, Tags{"go"}, article.Tags)
+	})
+}
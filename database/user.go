@@ -0,0 +1,226 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type userRepo struct {
+	db *sqlx.DB
+}
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrEmailTaken   = errors.New("email already taken")
+	ErrInvalidRole  = errors.New("invalid role")
+)
+
+const (
+	createUser = `
+	WITH inserted AS (
+		INSERT INTO "users" (name, email, password_hash, role)
+		VALUES ($1, $2, $3, $4) RETURNING *
+	)
+	SELECT
+		inserted.*,
+		ARRAY(
+			SELECT rp.permission FROM "role_permissions" rp
+			WHERE rp.role = inserted.role
+			ORDER BY rp.permission
+		) AS permissions
+	FROM inserted;`
+
+	getUsers = `
+	SELECT
+		u.id,
+		u.name,
+		u.email,
+		u.password_hash,
+		u.role,
+		ARRAY(
+			SELECT rp.permission FROM "role_permissions" rp
+			WHERE rp.role = u.role
+			ORDER BY rp.permission
+		) AS permissions,
+		u.created_at,
+		u.updated_at
+	FROM "users" u
+	ORDER BY u.id
+	LIMIT $1
+	OFFSET $2;`
+
+	countUsers = `
+	SELECT
+		count(*)
+	FROM "users";`
+
+	getUserByID = `
+	SELECT
+		u.id,
+		u.name,
+		u.email,
+		u.password_hash,
+		u.role,
+		ARRAY(
+			SELECT rp.permission FROM "role_permissions" rp
+			WHERE rp.role = u.role
+			ORDER BY rp.permission
+		) AS permissions,
+		u.created_at,
+		u.updated_at
+	FROM "users" u
+	WHERE u.id = $1;`
+
+	getUserByEmail = `
+	SELECT
+		u.id,
+		u.name,
+		u.email,
+		u.password_hash,
+		u.role,
+		ARRAY(
+			SELECT rp.permission FROM "role_permissions" rp
+			WHERE rp.role = u.role
+			ORDER BY rp.permission
+		) AS permissions,
+		u.created_at,
+		u.updated_at
+	FROM "users" u
+	WHERE u.email = $1;`
+
+	updateUserRole = `
+	WITH updated AS (
+		UPDATE "users"
+		SET
+			role = $2,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *
+	)
+	SELECT
+		updated.*,
+		ARRAY(
+			SELECT rp.permission FROM "role_permissions" rp
+			WHERE rp.role = updated.role
+			ORDER BY rp.permission
+		) AS permissions
+	FROM updated;`
+)
+
+func NewUserRepository(database Database) UserRepository {
+	return &userRepo{db: database.GetDB()}
+}
+
+// CreateUser creates the user with its role, users without one are readers
+func (repo *userRepo) CreateUser(ctx context.Context, user *User) (*User, error) {
+	newUser := &User{}
+
+	role := user.Role
+	if role == "" {
+		role = RoleReader
+	}
+
+	row := repo.db.QueryRowxContext(ctx, createUser,
+		user.Name,
+		user.Email,
+		user.PasswordHash,
+		role,
+	)
+
+	err := row.StructScan(newUser)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrEmailTaken
+		}
+		return nil, err
+	}
+
+	return newUser, nil
+}
+
+func (repo *userRepo) GetUsers(ctx context.Context, paging Paging) ([]User, PaginationData, error) {
+	rows, err := repo.db.QueryxContext(ctx, getUsers, paging.Limit(), paging.Offset())
+	if err != nil {
+		return []User{}, PaginationData{}, err
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		var user User
+		if err := rows.StructScan(&user); err != nil {
+			return []User{}, PaginationData{}, err
+		}
+
+		users = append(users, user)
+	}
+
+	var userCount int
+	if err = repo.db.QueryRowContext(ctx, countUsers).Scan(&userCount); err != nil {
+		return []User{}, PaginationData{}, err
+	}
+
+	paginationData := PaginationData{}
+	paginationData.Build(paging, len(users), userCount)
+
+	return users, paginationData, nil
+}
+
+func (repo *userRepo) GetUserByID(ctx context.Context, ID int) (*User, error) {
+	var user User
+
+	err := repo.db.QueryRowxContext(ctx, getUserByID, ID).StructScan(&user)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (repo *userRepo) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	var user User
+
+	err := repo.db.QueryRowxContext(ctx, getUserByEmail, email).StructScan(&user)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (repo *userRepo) UpdateUserRole(ctx context.Context, ID int, role string) (*User, error) {
+	var user User
+
+	err := repo.db.QueryRowxContext(ctx, updateUserRole, ID, role).StructScan(&user)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		if isForeignKeyViolation(err) {
+			return nil, ErrInvalidRole
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUser(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	repo := NewUserRepository(db)
+
+	payload := &User{
+		Name:         "Jane Doe",
+		Email:        "jane@example.com",
+		PasswordHash: "not-a-real-hash",
+	}
+
+	t.Run("create user", func(t *testing.T) {
+		user, err := repo.CreateUser(context.Background(), payload)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, user.ID)
+		require.Equal(t, payload.Name, user.Name)
+		require.Equal(t, payload.Email, user.Email)
+		require.Equal(t, payload.PasswordHash, user.PasswordHash)
+	})
+
+	t.Run("duplicate email", func(t *testing.T) {
+		_, err := repo.CreateUser(context.Background(), payload)
+		require.ErrorIs(t, err, ErrEmailTaken)
+	})
+}
+
+func TestGetUser(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	repo := NewUserRepository(db)
+
+	createdUser, err := repo.CreateUser(context.Background(), &User{
+		Name:         "John Doe",
+		Email:        "john@example.com",
+		PasswordHash: "not-a-real-hash",
+	})
+	require.NoError(t, err)
+
+	t.Run("find by id", func(t *testing.T) {
+		foundUser, err := repo.GetUserByID(context.Background(), createdUser.ID)
+		require.NoError(t, err)
+		require.Equal(t, createdUser, foundUser)
+	})
+
+	t.Run("find by email", func(t *testing.T) {
+		foundUser, err := repo.GetUserByEmail(context.Background(), createdUser.Email)
+		require.NoError(t, err)
+		require.Equal(t, createdUser, foundUser)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		foundUser, err := repo.GetUserByEmail(context.Background(), "nobody@example.com")
+		require.Nil(t, foundUser)
+		require.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	db, closeFn := initTestDB(t)
+	defer closeFn()
+
+	repo := NewUserRepository(db)
+
+	createdUser, err := repo.CreateUser(context.Background(), &User{
+		Name:         "John Doe",
+		Email:        "john@example.com",
+		PasswordHash: "not-a-real-hash",
+	})
+	require.NoError(t, err)
+	require.Equal(t, RoleReader, createdUser.Role)
+	require.Equal(t, []string{PermCommentsCreate}, []string(createdUser.Permissions))
+
+	t.Run("create with a role", func(t *testing.T) {
+		admin, err := repo.CreateUser(context.Background(), &User{
+			Name:         "Jane Doe",
+			Email:        "jane@example.com",
+			PasswordHash: "not-a-real-hash",
+			Role:         RoleAdmin,
+		})
+		require.NoError(t, err)
+		require.Equal(t, RoleAdmin, admin.Role)
+		require.True(t, admin.HasPermission(PermUsersManage))
+	})
+
+	t.Run("promote to editor", func(t *testing.T) {
+		updatedUser, err := repo.UpdateUserRole(context.Background(), createdUser.ID, RoleEditor)
+		require.NoError(t, err)
+		require.Equal(t, RoleEditor, updatedUser.Role)
+		require.True(t, updatedUser.HasPermission(PermArticlesUpdateAny))
+		require.False(t, updatedUser.HasPermission(PermUsersManage))
+	})
+
+	t.Run("demote to author", func(t *testing.T) {
+		updatedUser, err := repo.UpdateUserRole(context.Background(), createdUser.ID, RoleAuthor)
+		require.NoError(t, err)
+		require.True(t, updatedUser.HasPermission(PermArticlesCreate))
+		require.False(t, updatedUser.HasPermission(PermArticlesUpdateAny))
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		_, err := repo.UpdateUserRole(context.Background(), createdUser.ID, "overlord")
+		require.ErrorIs(t, err, ErrInvalidRole)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, err := repo.UpdateUserRole(context.Background(), 0, RoleEditor)
+		require.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
@@ -0,0 +1,275 @@
+// Package diff computes edit scripts between sequences of strings and
+// renders them as unified diffs or merged word segments.
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// maxEditDistance bounds the work done by Compute. Inputs that differ by more
+// than this are reported as a full replacement. The trace kept for backtracking
+// grows with the square of the distance, about 16MB at this bound.
+const maxEditDistance = 2000
+
+// maxWordEditDistance is the bound used by Words, which has many more tokens per article than lines
+const maxWordEditDistance = 1000
+
+type Edit struct {
+	Op   Op     `json:"op" example:"insert"`
+	Text string `json:"text" example:"lorem"`
+}
+
+// Compute returns the shortest edit script turning a into b using Myers' algorithm
+func Compute(a, b []string) []Edit {
+	return compute(a, b, maxEditDistance)
+}
+
+func compute(a, b []string, maxDistance int) []Edit {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxDistance)
+	offset := limit + 1
+
+	v := make([]int32, 2*offset+1)
+
+	// trace[d] keeps the diagonals -d-1 to d+1 of v as it was before step d,
+	// the only ones backtracking reads at that step
+	var trace [][]int32
+
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int32(nil), v[offset-d-1:offset+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = int(v[offset+k+1])
+			} else {
+				x = int(v[offset+k-1]) + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = int32(x)
+
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+
+	return replaceAll(a, b)
+}
+
+func backtrack(trace [][]int32, a, b []string) []Edit {
+	var edits []Edit
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		band := trace[d]
+		at := func(k int) int { return int(band[d+1+k]) }
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, Edit{Op: OpEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+
+		if d == 0 {
+			break
+		}
+
+		if x == prevX {
+			edits = append(edits, Edit{Op: OpInsert, Text: b[y-1]})
+			y--
+		} else {
+			edits = append(edits, Edit{Op: OpDelete, Text: a[x-1]})
+			x--
+		}
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
+
+func replaceAll(a, b []string) []Edit {
+	edits := make([]Edit, 0, len(a)+len(b))
+	for _, line := range a {
+		edits = append(edits, Edit{Op: OpDelete, Text: line})
+	}
+	for _, line := range b {
+		edits = append(edits, Edit{Op: OpInsert, Text: line})
+	}
+	return edits
+}
+
+// SplitLines splits text into lines without their trailing newlines
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+var wordPattern = regexp.MustCompile(`\s+|[^\s]+`)
+
+// Words returns a word level edit script between a and b. Whitespace is kept as
+// its own token and consecutive edits of the same kind are merged into one segment.
+func Words(a, b string) []Edit {
+	edits := compute(wordPattern.FindAllString(a, -1), wordPattern.FindAllString(b, -1), maxWordEditDistance)
+
+	var segments []Edit
+	for _, edit := range edits {
+		last := len(segments) - 1
+		if last >= 0 && segments[last].Op == edit.Op {
+			segments[last].Text += edit.Text
+			continue
+		}
+		segments = append(segments, edit)
+	}
+
+	return segments
+}
+
+// Unified renders a line level unified diff between a and b with the given
+// number of context lines around each change. It returns an empty string when
+// the texts are identical.
+func Unified(a, b, fromName, toName string, context int) string {
+	edits := Compute(SplitLines(a), SplitLines(b))
+
+	var sb strings.Builder
+	for _, h := range hunks(edits, context) {
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(h.aStart, h.aLines), hunkRange(h.bStart, h.bLines))
+		for _, edit := range h.edits {
+			switch edit.Op {
+			case OpEqual:
+				sb.WriteString(" ")
+			case OpInsert:
+				sb.WriteString("+")
+			case OpDelete:
+				sb.WriteString("-")
+			}
+			sb.WriteString(edit.Text)
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+type hunk struct {
+	aStart, aLines int
+	bStart, bLines int
+	edits          []Edit
+}
+
+func hunks(edits []Edit, context int) []hunk {
+	var result []hunk
+
+	i, prevEnd := 0, 0
+	for i < len(edits) {
+		// skip to the next change
+		for i < len(edits) && edits[i].Op == OpEqual {
+			i++
+		}
+		if i == len(edits) {
+			break
+		}
+
+		start := max(i-context, prevEnd)
+
+		// extend the hunk while the gap between changes fits in the surrounding context
+		end := i
+		for end < len(edits) {
+			if edits[end].Op != OpEqual {
+				end++
+				continue
+			}
+
+			gap := end
+			for gap < len(edits) && edits[gap].Op == OpEqual {
+				gap++
+			}
+
+			if gap == len(edits) || gap-end > 2*context {
+				end = min(end+context, len(edits))
+				break
+			}
+			end = gap
+		}
+
+		result = append(result, newHunk(edits, start, end))
+		i, prevEnd = end, end
+	}
+
+	return result
+}
+
+func newHunk(edits []Edit, start, end int) hunk {
+	// line numbers are 1-based and count the lines before the hunk
+	aLine, bLine := 1, 1
+	for _, edit := range edits[:start] {
+		if edit.Op != OpInsert {
+			aLine++
+		}
+		if edit.Op != OpDelete {
+			bLine++
+		}
+	}
+
+	h := hunk{aStart: aLine, bStart: bLine, edits: edits[start:end]}
+	for _, edit := range h.edits {
+		if edit.Op != OpInsert {
+			h.aLines++
+		}
+		if edit.Op != OpDelete {
+			h.bLines++
+		}
+	}
+
+	// an empty range points at the line before it
+	if h.aLines == 0 {
+		h.aStart--
+	}
+	if h.bLines == 0 {
+		h.bStart--
+	}
+
+	return h
+}
+
+func hunkRange(start, lines int) string {
+	if lines == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, lines)
+}
@@ -0,0 +1,148 @@
+package diff
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute(t *testing.T) {
+	apply := func(edits []Edit) (a, b []string) {
+		for _, edit := range edits {
+			if edit.Op != OpInsert {
+				a = append(a, edit.Text)
+			}
+			if edit.Op != OpDelete {
+				b = append(b, edit.Text)
+			}
+		}
+		return a, b
+	}
+
+	testCases := []struct {
+		name    string
+		a, b    []string
+		changes int
+	}{
+		{name: "identical", a: []string{"a", "b"}, b: []string{"a", "b"}, changes: 0},
+		{name: "both empty", changes: 0},
+		{name: "insert into empty", b: []string{"a", "b"}, changes: 2},
+		{name: "delete everything", a: []string{"a", "b"}, changes: 2},
+		{name: "replace middle", a: []string{"a", "b", "c"}, b: []string{"a", "x", "c"}, changes: 2},
+		{name: "classic", a: strings.Split("ABCABBA", ""), b: strings.Split("CBABAC", ""), changes: 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			edits := Compute(tc.a, tc.b)
+
+			a, b := apply(edits)
+			require.Equal(t, tc.a, a)
+			require.Equal(t, tc.b, b)
+
+			changes := 0
+			for _, edit := range edits {
+				if edit.Op != OpEqual {
+					changes++
+				}
+			}
+			require.Equal(t, tc.changes, changes)
+		})
+	}
+}
+
+func TestUnified(t *testing.T) {
+	t.Run("identical", func(t *testing.T) {
+		require.Empty(t, Unified("a\nb\n", "a\nb\n", "a", "b", 3))
+	})
+
+	t.Run("separate hunks", func(t *testing.T) {
+		a := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+		b := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n"
+
+		expected := `--- rev1
++++ rev2
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -11,3 +11,4 @@
+ k
+ l
+ m
++n
+`
+		require.Equal(t, expected, Unified(a, b, "rev1", "rev2", 3))
+	})
+
+	t.Run("close changes share a hunk", func(t *testing.T) {
+		expected := `--- rev1
++++ rev2
+@@ -1,3 +1,3 @@
+-a
++A
+ b
+-c
++C
+`
+		require.Equal(t, expected, Unified("a\nb\nc", "A\nb\nC", "rev1", "rev2", 1))
+	})
+
+	t.Run("from empty", func(t *testing.T) {
+		require.Equal(t, "--- rev1\n+++ rev2\n@@ -0,0 +1 @@\n+x\n", Unified("", "x", "rev1", "rev2", 3))
+	})
+}
+
+func TestWords(t *testing.T) {
+	edits := Words("the quick brown fox", "the slow brown dog")
+
+	require.Equal(t, []Edit{
+		{Op: OpEqual, Text: "the "},
+		{Op: OpDelete, Text: "quick"},
+		{Op: OpInsert, Text: "slow"},
+		{Op: OpEqual, Text: " brown "},
+		{Op: OpDelete, Text: "fox"},
+		{Op: OpInsert, Text: "dog"},
+	}, edits)
+}
+
+func TestComputeMemory(t *testing.T) {
+	unrelated := func(prefix string, n int) []string {
+		lines := make([]string, n)
+		for i := range lines {
+			lines[i] = prefix + strconv.Itoa(i)
+		}
+		return lines
+	}
+
+	testCases := []struct {
+		name   string
+		diff   func(a, b []string) []Edit
+		budget uint64
+	}{
+		{name: "lines", diff: Compute, budget: 32 << 20},
+		{name: "words", diff: func(a, b []string) []Edit { return compute(a, b, maxWordEditDistance) }, budget: 8 << 20},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := unrelated("a", 20000), unrelated("b", 20000)
+
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+
+			edits := tc.diff(a, b)
+
+			runtime.ReadMemStats(&after)
+			require.Len(t, edits, 40000)
+			require.Less(t, after.TotalAlloc-before.TotalAlloc, tc.budget)
+		})
+	}
+}
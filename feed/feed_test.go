@@ -0,0 +1,112 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2024, 6, 23, 21, 0, 0, 0, time.UTC)
+
+	return &Feed{
+		Title:       "Golang Blog",
+		Description: "Posts about Go",
+		Link:        "https://blog.example.com",
+		FeedURL:     "https://blog.example.com/api/feed.atom",
+		Updated:     published.Add(time.Hour),
+		Items: []Item{
+			{
+				ID:        "https://blog.example.com/articles/i-love-golang",
+				Title:     "I love Golang",
+				Link:      "https://blog.example.com/articles/i-love-golang",
+				Author:    "Ayo Awe",
+				Content:   "<p>Go & friends</p>",
+				Tags:      []string{"go", "tech"},
+				Published: &published,
+				Updated:   published.Add(time.Hour),
+			},
+		},
+	}
+}
+
+func TestRSS(t *testing.T) {
+	b, err := testFeed().RSS()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `<rss version="2.0"`)
+	require.Contains(t, string(b), `<description>&lt;p&gt;Go &amp; friends&lt;/p&gt;</description>`)
+	require.Contains(t, string(b), `<dc:creator>Ayo Awe</dc:creator>`)
+
+	var doc struct {
+		Channel struct {
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				GUID     string   `xml:"guid"`
+				PubDate  string   `xml:"pubDate"`
+				Category []string `xml:"category"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal(b, &doc))
+	require.Equal(t, "Sun, 23 Jun 2024 22:00:00 +0000", doc.Channel.LastBuildDate)
+	require.Len(t, doc.Channel.Items, 1)
+	require.Equal(t, "https://blog.example.com/articles/i-love-golang", doc.Channel.Items[0].GUID)
+	require.Equal(t, "Sun, 23 Jun 2024 21:00:00 +0000", doc.Channel.Items[0].PubDate)
+	require.Equal(t, []string{"go", "tech"}, doc.Channel.Items[0].Category)
+}
+
+func TestAtom(t *testing.T) {
+	b, err := testFeed().Atom()
+	require.NoError(t, err)
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			Updated string `xml:"updated"`
+			Author  string `xml:"author>name"`
+			Content struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(b, &doc))
+	require.Equal(t, "https://blog.example.com/api/feed.atom", doc.ID)
+	require.Equal(t, "2024-06-23T22:00:00Z", doc.Updated)
+	require.Len(t, doc.Entries, 1)
+	require.Equal(t, "2024-06-23T22:00:00Z", doc.Entries[0].Updated)
+	require.Equal(t, "Ayo Awe", doc.Entries[0].Author)
+	require.Equal(t, "html", doc.Entries[0].Content.Type)
+	require.Equal(t, "<p>Go & friends</p>", doc.Entries[0].Content.Value)
+}
+
+func TestJSON(t *testing.T) {
+	b, err := testFeed().JSON()
+	require.NoError(t, err)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(b, &doc))
+	require.Equal(t, "https://jsonfeed.org/version/1.1", doc["version"])
+
+	items := doc["items"].([]any)
+	require.Len(t, items, 1)
+
+	item := items[0].(map[string]any)
+	require.Equal(t, "<p>Go & friends</p>", item["content_html"])
+	require.Equal(t, "2024-06-23T22:00:00Z", item["date_modified"])
+	require.Equal(t, []any{map[string]any{"name": "Ayo Awe"}}, item["authors"])
+}
+
+func TestEmptyFeed(t *testing.T) {
+	f := testFeed()
+	f.Items = nil
+
+	b, err := f.JSON()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"items": []`)
+}
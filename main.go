@@ -1,30 +1,79 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/ayo-awe/blogging_api/api"
 	"github.com/ayo-awe/blogging_api/database"
 	_ "github.com/ayo-awe/blogging_api/docs"
+	"github.com/ayo-awe/blogging_api/ratelimit"
+	"github.com/ayo-awe/blogging_api/scheduler"
+	"github.com/ayo-awe/blogging_api/storage"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type Config struct {
-	PORT         int    `envconfig:"PORT" default:"8080"`
-	DATABASE_URL string `envconfig:"DB_URL" required:"true"`
+	PORT         int           `envconfig:"PORT" default:"8080"`
+	DATABASE_URL string        `envconfig:"DB_URL" required:"true"`
+	JWT_SECRET   string        `envconfig:"JWT_SECRET" required:"true"`
+	TOKEN_TTL    time.Duration `envconfig:"TOKEN_TTL" default:"24h"`
+	BASE_URL     string        `envconfig:"BASE_URL" default:"http://localhost:8080"`
+	API_URL      string        `envconfig:"API_URL" default:"http://localhost:8080/api"`
+	SITE_TITLE   string        `envconfig:"SITE_TITLE" default:"Golang Blog"`
+
+	ADMIN_NAME     string `envconfig:"ADMIN_NAME" default:"Admin"`
+	ADMIN_EMAIL    string `envconfig:"ADMIN_EMAIL"`
+	ADMIN_PASSWORD string `envconfig:"ADMIN_PASSWORD"`
+
+	STORAGE_DRIVER  string `envconfig:"STORAGE_DRIVER" default:"local"`
+	STORAGE_DIR     string `envconfig:"STORAGE_DIR" default:"uploads"`
+	S3_ENDPOINT     string `envconfig:"S3_ENDPOINT"`
+	S3_REGION       string `envconfig:"S3_REGION" default:"us-east-1"`
+	S3_BUCKET       string `envconfig:"S3_BUCKET"`
+	S3_ACCESS_KEY   string `envconfig:"S3_ACCESS_KEY"`
+	S3_SECRET_KEY   string `envconfig:"S3_SECRET_KEY"`
+	S3_PATH_STYLE   bool   `envconfig:"S3_PATH_STYLE" default:"false"`
+	MAX_UPLOAD_SIZE int64  `envconfig:"MAX_UPLOAD_SIZE" default:"10485760"`
+
+	CACHE_CONTROL_ARTICLES string `envconfig:"CACHE_CONTROL_ARTICLES" default:"public, no-cache"`
+	CACHE_CONTROL_TAGS     string `envconfig:"CACHE_CONTROL_TAGS" default:"public, max-age=300"`
+	CACHE_CONTROL_FEEDS    string `envconfig:"CACHE_CONTROL_FEEDS" default:"public, max-age=900"`
+	CACHE_CONTROL_SITEMAP  string `envconfig:"CACHE_CONTROL_SITEMAP" default:"public, max-age=3600"`
+
+	RATE_LIMIT_STORE  string        `envconfig:"RATE_LIMIT_STORE" default:"memory"`
+	RATE_LIMIT_READS  int           `envconfig:"RATE_LIMIT_READS" default:"300"`
+	RATE_LIMIT_WRITES int           `envconfig:"RATE_LIMIT_WRITES" default:"60"`
+	RATE_LIMIT_PERIOD time.Duration `envconfig:"RATE_LIMIT_PERIOD" default:"1m"`
+	TRUST_PROXY       bool          `envconfig:"TRUST_PROXY" default:"false"`
+
+	SCHEDULER_INTERVAL time.Duration `envconfig:"SCHEDULER_INTERVAL" default:"1m"`
+	SHUTDOWN_TIMEOUT   time.Duration `envconfig:"SHUTDOWN_TIMEOUT" default:"10s"`
 }
 
 //	@title			Golang Blogging API
 //	@version		1.0
 //	@description	This is a minimalist blogging api.
 
+//	@securityDefinitions.apikey	BearerAuth
+//	@in							header
+//	@name						Authorization
+//	@description				Type "Bearer" followed by a space and the token returned from login.
+
 // @BasePath	/api
 func main() {
 	if err := run(); err != nil {
@@ -47,21 +96,163 @@ func run() error {
 	}
 
 	repo := database.NewArticleRepository(db)
-	app := api.NewApplication(logger, repo)
+	users := database.NewUserRepository(db)
+
+	if err = seedAdmin(context.Background(), cfg, users, logger); err != nil {
+		return err
+	}
+
+	comments := database.NewCommentRepository(db)
+	tags := database.NewTagRepository(db)
+	media := database.NewMediaRepository(db)
+	tokens := api.NewTokenIssuer(cfg.JWT_SECRET, cfg.TOKEN_TTL)
+	site := api.Site{
+		Title:   cfg.SITE_TITLE,
+		BaseURL: strings.TrimSuffix(cfg.BASE_URL, "/"),
+		APIURL:  strings.TrimSuffix(cfg.API_URL, "/"),
+	}
 
-	r.Use(middleware.Logger)
+	files, err := newStorage(cfg)
+	if err != nil {
+		return err
+	}
+
+	uploads := api.Uploads{Storage: files, MaxSize: cfg.MAX_UPLOAD_SIZE}
+	cache := api.CachePolicies{
+		Articles: cfg.CACHE_CONTROL_ARTICLES,
+		Tags:     cfg.CACHE_CONTROL_TAGS,
+		Feeds:    cfg.CACHE_CONTROL_FEEDS,
+		Sitemap:  cfg.CACHE_CONTROL_SITEMAP,
+	}
+
+	limiter, err := newRateLimitStore(cfg, db, logger)
+	if err != nil {
+		return err
+	}
+
+	limits := api.RateLimits{
+		Store:  limiter,
+		Reads:  ratelimit.Limit{Requests: cfg.RATE_LIMIT_READS, Period: cfg.RATE_LIMIT_PERIOD},
+		Writes: ratelimit.Limit{Requests: cfg.RATE_LIMIT_WRITES, Period: cfg.RATE_LIMIT_PERIOD},
+	}
+	app := api.NewApplication(logger, site, repo, users, comments, tags, media, uploads, cache, limits, tokens)
+
+	// behind a proxy every request comes from the proxy, so clients are told apart by the forwarded address
+	if cfg.TRUST_PROXY {
+		r.Use(middleware.RealIP)
+	}
+	r.Use(middleware.RequestID, middleware.Logger)
 	r.Mount("/api", app.BuildRoutes())
 	r.Get("/swagger/*", httpSwagger.Handler())
 
-	fmt.Println("starting server on port 8080")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sched := scheduler.NewScheduler(logger, repo, cfg.SCHEDULER_INTERVAL)
+	sched.Start(ctx)
+	defer sched.Stop()
+
 	addr := fmt.Sprintf(":%d", cfg.PORT)
-	if err = http.ListenAndServe(addr, r); err != nil {
+	server := &http.Server{Addr: addr, Handler: r}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		fmt.Printf("starting server on port %d\n", cfg.PORT)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.SHUTDOWN_TIMEOUT)
+	defer cancel()
+
+	return server.Shutdown(shutdownCtx)
+}
+
+// seedAdmin creates the admin account named by ADMIN_EMAIL if no account uses that email yet,
+// so a new deployment has someone who can promote other users
+func seedAdmin(ctx context.Context, cfg *Config, users database.UserRepository, logger *slog.Logger) error {
+	if cfg.ADMIN_EMAIL == "" {
+		return nil
+	}
+
+	existing, err := users.GetUserByEmail(ctx, cfg.ADMIN_EMAIL)
+	if err == nil {
+		// an account registered with the email is not trusted to be the admin's
+		if existing.Role != database.RoleAdmin {
+			logger.Warn("ADMIN_EMAIL belongs to an account that is not an admin, it was not promoted", "email", cfg.ADMIN_EMAIL)
+		}
+		return nil
+	}
+
+	if !errors.Is(err, database.ErrUserNotFound) {
+		return err
+	}
+
+	if len(cfg.ADMIN_PASSWORD) < 8 || len(cfg.ADMIN_PASSWORD) > 72 {
+		return errors.New("ADMIN_PASSWORD must be 8 to 72 characters long when ADMIN_EMAIL is set")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(cfg.ADMIN_PASSWORD), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	_, err = users.CreateUser(ctx, &database.User{
+		Name:         cfg.ADMIN_NAME,
+		Email:        cfg.ADMIN_EMAIL,
+		PasswordHash: string(hash),
+		Role:         database.RoleAdmin,
+	})
+	if err != nil {
+		// another instance starting at the same time created it
+		if errors.Is(err, database.ErrEmailTaken) {
+			return nil
+		}
 		return err
 	}
 
+	logger.Info("created admin account", "email", cfg.ADMIN_EMAIL)
 	return nil
 }
 
+func newStorage(cfg *Config) (storage.Storage, error) {
+	switch cfg.STORAGE_DRIVER {
+	case "local":
+		return storage.NewLocal(cfg.STORAGE_DIR)
+	case "s3":
+		return storage.NewS3(storage.S3Config{
+			Endpoint:  cfg.S3_ENDPOINT,
+			Region:    cfg.S3_REGION,
+			Bucket:    cfg.S3_BUCKET,
+			AccessKey: cfg.S3_ACCESS_KEY,
+			SecretKey: cfg.S3_SECRET_KEY,
+			PathStyle: cfg.S3_PATH_STYLE,
+		})
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q, expected local or s3", cfg.STORAGE_DRIVER)
+	}
+}
+
+func newRateLimitStore(cfg *Config, db database.Database, logger *slog.Logger) (ratelimit.Store, error) {
+	switch cfg.RATE_LIMIT_STORE {
+	case "memory":
+		return ratelimit.NewMemory(), nil
+	case "postgres":
+		return database.NewRateLimitStore(db, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown RATE_LIMIT_STORE %q, expected memory or postgres", cfg.RATE_LIMIT_STORE)
+	}
+}
+
 func LoadConfig() (*Config, error) {
 	var config Config
 
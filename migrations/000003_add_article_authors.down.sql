@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS articles_author_id_idx;
+
+ALTER TABLE "articles" DROP COLUMN IF EXISTS author_id;
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS role;
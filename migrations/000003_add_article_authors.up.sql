@@ -0,0 +1,6 @@
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'author';
+
+-- authors are the users who wrote the article, so there is no separate authors table to keep in sync
+ALTER TABLE "articles" ADD COLUMN IF NOT EXISTS author_id INTEGER REFERENCES "users" (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS articles_author_id_idx ON "articles" (author_id);
@@ -0,0 +1,6 @@
+ALTER TABLE "users" ALTER COLUMN role SET DEFAULT 'author';
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS users_role_fkey;
+
+DROP TABLE IF EXISTS "role_permissions";
+DROP TABLE IF EXISTS "permissions";
+DROP TABLE IF EXISTS "roles";
@@ -0,0 +1,54 @@
+CREATE TABLE IF NOT EXISTS "roles" (
+	name VARCHAR(20) PRIMARY KEY,
+	description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS "permissions" (
+	name VARCHAR(50) PRIMARY KEY,
+	description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS "role_permissions" (
+	role VARCHAR(20) NOT NULL REFERENCES "roles" (name) ON UPDATE CASCADE ON DELETE CASCADE,
+	permission VARCHAR(50) NOT NULL REFERENCES "permissions" (name) ON UPDATE CASCADE ON DELETE CASCADE,
+	PRIMARY KEY (role, permission)
+);
+
+INSERT INTO "roles" (name, description) VALUES
+	('reader', 'Can read published content'),
+	('author', 'Can write and manage their own articles'),
+	('editor', 'Can manage any article'),
+	('admin', 'Can manage any article and user accounts')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "permissions" (name, description) VALUES
+	('articles:create', 'Create articles'),
+	('articles:update', 'Update own articles'),
+	('articles:delete', 'Delete own articles'),
+	('articles:update:any', 'Update any article'),
+	('articles:delete:any', 'Delete any article'),
+	('users:manage', 'List users and change their roles')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "role_permissions" (role, permission) VALUES
+	('author', 'articles:create'),
+	('author', 'articles:update'),
+	('author', 'articles:delete'),
+	('editor', 'articles:create'),
+	('editor', 'articles:update'),
+	('editor', 'articles:delete'),
+	('editor', 'articles:update:any'),
+	('editor', 'articles:delete:any'),
+	('admin', 'articles:create'),
+	('admin', 'articles:update'),
+	('admin', 'articles:delete'),
+	('admin', 'articles:update:any'),
+	('admin', 'articles:delete:any'),
+	('admin', 'users:manage')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE "users"
+	ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES "roles" (name) ON UPDATE CASCADE;
+
+-- existing accounts stay authors, new ones are readers until promoted
+ALTER TABLE "users" ALTER COLUMN role SET DEFAULT 'reader';
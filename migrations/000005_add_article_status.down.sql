@@ -0,0 +1,11 @@
+DELETE FROM "permissions" WHERE name IN ('articles:publish', 'articles:publish:any');
+
+DROP INDEX IF EXISTS articles_status_published_at_idx;
+
+UPDATE "articles" SET published_at = created_at WHERE published_at IS NULL;
+
+ALTER TABLE "articles"
+	ALTER COLUMN published_at SET NOT NULL,
+	ALTER COLUMN published_at SET DEFAULT CURRENT_TIMESTAMP,
+	DROP COLUMN IF EXISTS created_at,
+	DROP COLUMN IF EXISTS status;
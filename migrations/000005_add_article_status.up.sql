@@ -0,0 +1,27 @@
+-- articles created before this migration were already public
+ALTER TABLE "articles"
+	ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+		CHECK (status IN ('draft', 'published', 'archived')),
+	ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE "articles" SET created_at = published_at;
+
+ALTER TABLE "articles"
+	ALTER COLUMN status SET DEFAULT 'draft',
+	ALTER COLUMN published_at DROP DEFAULT,
+	ALTER COLUMN published_at DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS articles_status_published_at_idx ON "articles" (status, published_at DESC);
+
+INSERT INTO "permissions" (name, description) VALUES
+	('articles:publish', 'Publish, unpublish and archive own articles'),
+	('articles:publish:any', 'Publish, unpublish and archive any article')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "role_permissions" (role, permission) VALUES
+	('author', 'articles:publish'),
+	('editor', 'articles:publish'),
+	('editor', 'articles:publish:any'),
+	('admin', 'articles:publish'),
+	('admin', 'articles:publish:any')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS articles_scheduled_idx;
+
+ALTER TABLE "articles" DROP COLUMN IF EXISTS publish_at;
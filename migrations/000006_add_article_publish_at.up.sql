@@ -0,0 +1,4 @@
+ALTER TABLE "articles" ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS articles_scheduled_idx ON "articles" (publish_at)
+	WHERE status = 'draft' AND publish_at IS NOT NULL;
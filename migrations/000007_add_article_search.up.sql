@@ -0,0 +1,7 @@
+ALTER TABLE "articles" ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+	GENERATED ALWAYS AS (
+		setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(content, '')), 'B')
+	) STORED;
+
+CREATE INDEX IF NOT EXISTS articles_search_vector_idx ON "articles" USING GIN (search_vector);
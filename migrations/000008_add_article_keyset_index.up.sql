@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS articles_keyset_idx ON "articles" ((COALESCE(published_at, created_at)) DESC, id DESC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "article_revisions";
+
+ALTER TABLE "articles" DROP COLUMN IF EXISTS revision;
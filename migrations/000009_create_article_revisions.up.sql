@@ -0,0 +1,19 @@
+ALTER TABLE "articles" ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS "article_revisions" (
+	id SERIAL PRIMARY KEY,
+	article_id INTEGER NOT NULL REFERENCES "articles" (id) ON DELETE CASCADE,
+	revision INTEGER NOT NULL,
+	title VARCHAR(255) NOT NULL,
+	content TEXT NOT NULL,
+	tags JSONB NOT NULL DEFAULT '[]'::JSONB,
+	editor_id INTEGER REFERENCES "users" (id) ON DELETE SET NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (article_id, revision)
+);
+
+-- the current state of existing articles becomes their first revision
+INSERT INTO "article_revisions" (article_id, revision, title, content, tags, editor_id, created_at)
+SELECT id, 1, title, content, tags, author_id, updated_at
+FROM "articles"
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DELETE FROM "permissions" WHERE name IN ('comments:create', 'comments:moderate');
+
+DROP TABLE IF EXISTS "comments";
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS "comments" (
+	id SERIAL PRIMARY KEY,
+	article_id INTEGER NOT NULL REFERENCES "articles" (id) ON DELETE CASCADE,
+	user_id INTEGER REFERENCES "users" (id) ON DELETE SET NULL,
+	body TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS comments_article_id_idx ON "comments" (article_id, created_at);
+
+INSERT INTO "permissions" (name, description) VALUES
+	('comments:create', 'Comment on articles'),
+	('comments:moderate', 'Delete any comment')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "role_permissions" (role, permission) VALUES
+	('reader', 'comments:create'),
+	('author', 'comments:create'),
+	('editor', 'comments:create'),
+	('editor', 'comments:moderate'),
+	('admin', 'comments:create'),
+	('admin', 'comments:moderate')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,16 @@
+UPDATE "permissions"
+SET description = 'Delete any comment'
+WHERE name = 'comments:moderate';
+
+DROP INDEX IF EXISTS comments_status_idx;
+DROP INDEX IF EXISTS comments_parent_id_idx;
+
+DELETE FROM "comments" WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE "comments"
+	DROP COLUMN IF EXISTS deleted_at,
+	DROP COLUMN IF EXISTS moderated_at,
+	DROP COLUMN IF EXISTS moderated_by,
+	DROP COLUMN IF EXISTS status,
+	DROP COLUMN IF EXISTS guest_name,
+	DROP COLUMN IF EXISTS parent_id;
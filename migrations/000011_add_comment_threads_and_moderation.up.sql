@@ -0,0 +1,19 @@
+-- comments made before moderation existed stay visible
+ALTER TABLE "comments"
+	ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES "comments" (id) ON DELETE CASCADE,
+	ADD COLUMN IF NOT EXISTS guest_name VARCHAR(100),
+	ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'approved'
+		CHECK (status IN ('pending', 'approved', 'rejected', 'spam')),
+	ADD COLUMN IF NOT EXISTS moderated_by INTEGER REFERENCES "users" (id) ON DELETE SET NULL,
+	ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMPTZ,
+	-- deleted comments are kept without their body and author, so the replies under them stay in the thread
+	ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+ALTER TABLE "comments" ALTER COLUMN status SET DEFAULT 'pending';
+
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON "comments" (parent_id);
+CREATE INDEX IF NOT EXISTS comments_status_idx ON "comments" (status, created_at);
+
+UPDATE "permissions"
+SET description = 'Approve, reject and delete any comment'
+WHERE name = 'comments:moderate';
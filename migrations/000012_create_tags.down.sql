@@ -0,0 +1,4 @@
+DELETE FROM "permissions" WHERE name = 'tags:manage';
+
+DROP INDEX IF EXISTS articles_tags_idx;
+DROP TABLE IF EXISTS "tags";
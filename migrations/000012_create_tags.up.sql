@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS "tags" (
+	name TEXT PRIMARY KEY,
+	slug TEXT NOT NULL UNIQUE,
+	description TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS articles_tags_idx ON "articles" USING GIN (tags);
+
+-- tags already used by articles, names that slugify the same way get numeric suffixes
+INSERT INTO "tags" (name, slug)
+SELECT name, CASE WHEN n = 1 THEN base ELSE base || '-' || n END
+FROM (
+	SELECT name, base, row_number() OVER (PARTITION BY base ORDER BY name) AS n
+	FROM (
+		SELECT DISTINCT
+			name,
+			COALESCE(NULLIF(trim(BOTH '-' FROM regexp_replace(lower(name), '[^[:alnum:]]+', '-', 'g')), ''), 'tag') AS base
+		FROM "articles", jsonb_array_elements_text(tags) AS name
+	) named
+) numbered
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "permissions" (name, description) VALUES
+	('tags:manage', 'Describe, rename and merge tags')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "role_permissions" (role, permission) VALUES
+	('editor', 'tags:manage'),
+	('admin', 'tags:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "article_slugs";
+
+ALTER TABLE "articles"
+	DROP CONSTRAINT IF EXISTS articles_slug_key,
+	DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,37 @@
+ALTER TABLE "articles" ADD COLUMN IF NOT EXISTS slug TEXT;
+
+-- existing articles with the same title get numeric suffixes, oldest first
+UPDATE "articles" a
+SET slug = CASE WHEN numbered.n = 1 THEN numbered.base ELSE numbered.base || '-' || numbered.n END
+FROM (
+	SELECT id, base, row_number() OVER (PARTITION BY base ORDER BY id) AS n
+	FROM (
+		SELECT
+			id,
+			COALESCE(NULLIF(trim(BOTH '-' FROM regexp_replace(lower(title), '[^[:alnum:]]+', '-', 'g')), ''), 'article') AS base
+		FROM "articles"
+	) named
+) numbered
+WHERE a.id = numbered.id;
+
+-- a generated suffix can clash with another title, e.g. "Go" twice and "Go 2"
+UPDATE "articles"
+SET slug = slug || '-' || id
+WHERE id IN (
+	SELECT id
+	FROM (SELECT id, row_number() OVER (PARTITION BY slug ORDER BY id) AS n FROM "articles") duplicated
+	WHERE n > 1
+);
+
+ALTER TABLE "articles"
+	ALTER COLUMN slug SET NOT NULL,
+	ADD CONSTRAINT articles_slug_key UNIQUE (slug);
+
+-- slugs articles used to have, kept so old links can be redirected
+CREATE TABLE IF NOT EXISTS "article_slugs" (
+	slug TEXT PRIMARY KEY,
+	article_id INTEGER NOT NULL REFERENCES "articles" (id) ON DELETE CASCADE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS article_slugs_article_id_idx ON "article_slugs" (article_id);
@@ -0,0 +1,3 @@
+ALTER TABLE "articles"
+	DROP COLUMN IF EXISTS content_html,
+	DROP COLUMN IF EXISTS content_format;
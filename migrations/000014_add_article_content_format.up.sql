@@ -0,0 +1,5 @@
+-- content_html of existing articles stays NULL until their next update, the API renders it on read until then
+ALTER TABLE "articles"
+	ADD COLUMN IF NOT EXISTS content_format VARCHAR(20) NOT NULL DEFAULT 'markdown'
+		CHECK (content_format IN ('markdown', 'plain')),
+	ADD COLUMN IF NOT EXISTS content_html TEXT;
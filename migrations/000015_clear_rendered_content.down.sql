@@ -0,0 +1 @@
+UPDATE "articles" SET content_html = NULL;
@@ -0,0 +1,2 @@
+-- rendering now highlights code and anchors headings, the API re-renders cleared articles on read until they are next saved
+UPDATE "articles" SET content_html = NULL;
@@ -0,0 +1,3 @@
+DELETE FROM "permissions" WHERE name = 'media:upload';
+
+DROP TABLE IF EXISTS "media";
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS "media" (
+	id SERIAL PRIMARY KEY,
+	storage_key TEXT NOT NULL UNIQUE,
+	filename TEXT NOT NULL,
+	content_type TEXT NOT NULL,
+	size BIGINT NOT NULL,
+	owner_id INTEGER REFERENCES "users" (id) ON DELETE SET NULL,
+	article_id INTEGER REFERENCES "articles" (id) ON DELETE SET NULL,
+	-- stays true once the article is deleted, so the file is kept private instead of becoming public
+	attached BOOLEAN NOT NULL DEFAULT false,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS media_article_id_idx ON "media" (article_id);
+
+INSERT INTO "permissions" (name, description) VALUES
+	('media:upload', 'Upload images and attachments')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "role_permissions" (role, permission) VALUES
+	('author', 'media:upload'),
+	('editor', 'media:upload'),
+	('admin', 'media:upload')
+ON CONFLICT DO NOTHING;
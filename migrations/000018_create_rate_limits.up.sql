@@ -0,0 +1,9 @@
+-- buckets are cheap to lose, a crash only refills them, so the table skips the write ahead log
+CREATE UNLOGGED TABLE IF NOT EXISTS "rate_limits" (
+	key TEXT PRIMARY KEY,
+	tokens DOUBLE PRECISION NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	full_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS rate_limits_full_at_idx ON "rate_limits" (full_at);
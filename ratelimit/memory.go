@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is how many takes pass between removals of full buckets
+const sweepEvery = 1000
+
+type memoryBucket struct {
+	Bucket
+	fullAt time.Time
+}
+
+// Memory keeps buckets in the process, so each replica of the server counts requests separately
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	takes   int
+	now     func() time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{buckets: map[string]*memoryBucket{}, now: time.Now}
+}
+
+func (m *Memory) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &memoryBucket{Bucket: NewBucket(limit, now)}
+		m.buckets[key] = b
+	}
+
+	result := b.Take(limit, now)
+	b.fullAt = now.Add(result.Reset)
+
+	m.takes++
+	if m.takes%sweepEvery == 0 {
+		m.sweep(now)
+	}
+
+	return result, nil
+}
+
+// sweep forgets full buckets, they are recreated full when needed
+func (m *Memory) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if !b.fullAt.After(now) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package ratelimit limits requests with token buckets kept in a pluggable store.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit allows bursts of up to Requests, refilling an empty bucket over Period
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Enabled reports whether the limit restricts anything, a zero limit lets every request through
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// rate is the number of tokens added per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until a token is available, it is zero when the request was allowed
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// Store keeps a bucket per key
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Bucket is the state of a token bucket, stores persist it between requests
+type Bucket struct {
+	Tokens    float64   `db:"tokens"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// NewBucket returns a full bucket
+func NewBucket(limit Limit, now time.Time) Bucket {
+	return Bucket{Tokens: float64(limit.Requests), UpdatedAt: now}
+}
+
+// Take refills the bucket for the time passed since it was last updated and takes a token if there is one
+func (b *Bucket) Take(limit Limit, now time.Time) Result {
+	rate := limit.rate()
+	elapsed := math.Max(0, now.Sub(b.UpdatedAt).Seconds())
+
+	b.Tokens = math.Min(float64(limit.Requests), b.Tokens+elapsed*rate)
+	b.UpdatedAt = now
+
+	result := Result{Allowed: b.Tokens >= 1, Limit: limit.Requests}
+	if result.Allowed {
+		b.Tokens--
+	} else {
+		result.RetryAfter = seconds((1 - b.Tokens) / rate)
+	}
+
+	result.Remaining = int(b.Tokens)
+	result.Reset = seconds((float64(limit.Requests) - b.Tokens) / rate)
+
+	return result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucket(t *testing.T) {
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+	now := time.Date(2024, 6, 23, 21, 0, 0, 0, time.UTC)
+	b := NewBucket(limit, now)
+
+	for remaining := 2; remaining >= 0; remaining-- {
+		result := b.Take(limit, now)
+		require.True(t, result.Allowed)
+		require.Equal(t, 3, result.Limit)
+		require.Equal(t, remaining, result.Remaining)
+	}
+
+	result := b.Take(limit, now)
+	require.False(t, result.Allowed)
+	require.Equal(t, time.Second, result.RetryAfter)
+	require.Equal(t, 3*time.Second, result.Reset)
+
+	// one token is back after a second
+	result = b.Take(limit, now.Add(time.Second))
+	require.True(t, result.Allowed)
+	require.Equal(t, 0, result.Remaining)
+
+	// and the bucket never holds more than the limit
+	result = b.Take(limit, now.Add(time.Hour))
+	require.True(t, result.Allowed)
+	require.Equal(t, 2, result.Remaining)
+	require.Equal(t, time.Second, result.Reset)
+}
+
+func TestMemory(t *testing.T) {
+	now := time.Date(2024, 6, 23, 21, 0, 0, 0, time.UTC)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	limit := Limit{Requests: 1, Period: time.Minute}
+
+	result, err := m.Take(context.Background(), "ip:192.0.2.1", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	result, err = m.Take(context.Background(), "ip:192.0.2.1", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, time.Minute, result.RetryAfter)
+
+	t.Run("keys have their own buckets", func(t *testing.T) {
+		result, err := m.Take(context.Background(), "ip:192.0.2.2", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	})
+
+	t.Run("full buckets are swept", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		m.sweep(now)
+		require.Empty(t, m.buckets)
+	})
+}
@@ -0,0 +1,178 @@
+// Package render turns article content into HTML that is safe to embed in a page.
+package render
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// HIGHLIGHT_STYLE is the chroma style HighlightCSS colours code blocks with
+const HIGHLIGHT_STYLE = "github"
+
+var (
+	// highlighted code gets classes rather than inline styles, so authors can't style their own HTML.
+	// The prefix keeps the classes apart from the ones a page uses.
+	highlightOptions = []chromahtml.Option{chromahtml.WithClasses(true), chromahtml.ClassPrefix("hl-")}
+
+	// raw HTML is let through goldmark and then stripped down by policy
+	markdown = goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			highlighting.NewHighlighting(
+				highlighting.WithStyle(HIGHLIGHT_STYLE),
+				highlighting.WithFormatOptions(highlightOptions...),
+			),
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+
+	policy = newPolicy()
+
+	paragraphBreak = regexp.MustCompile(`\n\s*\n`)
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// fenced code blocks in languages the highlighter doesn't know keep their language for the client
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	// token classes from the syntax highlighter, styled by HighlightCSS
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^hl-[a-z0-9-]+$`)).OnElements("pre", "span")
+	// GFM task lists
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
+
+// Heading is an entry in an article's table of contents, ID is the anchor of the heading in the rendered HTML
+type Heading struct {
+	Level    int       `json:"level" example:"2"`
+	Title    string    `json:"title" example:"Getting started"`
+	ID       string    `json:"id" example:"getting-started"`
+	Children []Heading `json:"children"`
+}
+
+// Markdown renders CommonMark with the GitHub extensions (tables, strikethrough, autolinks and task lists)
+// and sanitizes the result against an allowlist of elements and attributes. Fenced code blocks are highlighted
+// by language and headings get anchors derived from their text.
+func Markdown(src string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(src), &buf); err != nil {
+		return "", err
+	}
+
+	return Sanitize(buf.String()), nil
+}
+
+// TableOfContents lists the headings of a Markdown document nested by level,
+// with the same anchors Markdown gives them.
+func TableOfContents(src string) []Heading {
+	source := []byte(src)
+	doc := markdown.Parser().Parse(text.NewReader(source))
+
+	root := &Heading{Children: []Heading{}}
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		heading, ok := child.(*ast.Heading)
+		if !ok {
+			continue
+		}
+
+		id, _ := heading.AttributeString("id")
+		idBytes, _ := id.([]byte)
+
+		entry := Heading{
+			Level:    heading.Level,
+			Title:    plainText(heading, source),
+			ID:       string(idBytes),
+			Children: []Heading{},
+		}
+
+		// descend into the last entry while it is a higher level heading
+		parent := root
+		for len(parent.Children) > 0 && parent.Children[len(parent.Children)-1].Level < entry.Level {
+			parent = &parent.Children[len(parent.Children)-1]
+		}
+		parent.Children = append(parent.Children, entry)
+	}
+
+	return root.Children
+}
+
+// plainText concatenates the text inside node, dropping emphasis, links and other markup
+func plainText(node ast.Node, source []byte) string {
+	var b strings.Builder
+
+	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.Text:
+			b.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(n.Value)
+		case *ast.CodeSpan:
+			for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+				if t, ok := c.(*ast.Text); ok {
+					b.Write(t.Segment.Value(source))
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(b.String())
+}
+
+// PlainText escapes src and wraps each blank line separated block in a paragraph, keeping its line breaks.
+func PlainText(src string) string {
+	var b strings.Builder
+
+	for _, block := range paragraphBreak.Split(strings.ReplaceAll(src, "\r\n", "\n"), -1) {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(block), "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+
+	return b.String()
+}
+
+// HighlightCSS returns the stylesheet colouring the code blocks rendered by Markdown
+func HighlightCSS() (string, error) {
+	var buf bytes.Buffer
+	if err := chromahtml.New(highlightOptions...).WriteCSS(&buf, styles.Get(HIGHLIGHT_STYLE)); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// Sanitize removes every element and attribute not allowed in user written content, such as scripts,
+// event handlers and javascript: URLs.
+func Sanitize(unsafe string) string {
+	return policy.Sanitize(unsafe)
+}
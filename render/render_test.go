@@ -0,0 +1,105 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdown(t *testing.T) {
+	t.Run("commonmark", func(t *testing.T) {
+		out, err := Markdown("# Title\n\nSome *emphasis* and a [link](https://example.com).")
+		require.NoError(t, err)
+		require.Contains(t, out, `<h1 id="title">Title</h1>`)
+		require.Contains(t, out, "<em>emphasis</em>")
+		require.Contains(t, out, `<a href="https://example.com" rel="nofollow">link</a>`)
+	})
+
+	t.Run("github extensions", func(t *testing.T) {
+		out, err := Markdown("| a | b |\n|---|---|\n| 1 | 2 |\n\n~~gone~~\n\n- [x] done")
+		require.NoError(t, err)
+		require.Contains(t, out, "<table>")
+		require.Contains(t, out, "<del>gone</del>")
+		require.Contains(t, out, `<input checked="" disabled="" type="checkbox"`)
+	})
+
+	t.Run("code blocks are highlighted", func(t *testing.T) {
+		out, err := Markdown("```go\nfmt.Println(\"hi\")\n```")
+		require.NoError(t, err)
+		require.Contains(t, out, `<span class="hl-s">&#34;hi&#34;</span>`)
+		require.NotContains(t, out, "style=")
+	})
+
+	t.Run("authors can't style their HTML", func(t *testing.T) {
+		out, err := Markdown(`<span style="color: #fff" class="note hl-s">hidden</span>`)
+		require.NoError(t, err)
+		require.NotContains(t, out, "style=")
+		require.NotContains(t, out, "note")
+	})
+
+	t.Run("unknown languages keep their class", func(t *testing.T) {
+		out, err := Markdown("```nosuchlang\nx\n```")
+		require.NoError(t, err)
+		require.Contains(t, out, `<code class="language-nosuchlang">`)
+	})
+
+	t.Run("headings have anchors", func(t *testing.T) {
+		out, err := Markdown("## Getting *started*\n\n## Getting started")
+		require.NoError(t, err)
+		require.Contains(t, out, `<h2 id="getting-started">Getting <em>started</em></h2>`)
+		require.Contains(t, out, `<h2 id="getting-started-1">Getting started</h2>`)
+	})
+
+	xssTests := map[string]string{
+		"script tag":         "<script>alert(1)</script>",
+		"event handler":      `<img src="x.png" onerror="alert(1)">`,
+		"javascript link":    "[click](javascript:alert(1))",
+		"javascript href":    `<a href="javascript:alert(1)">click</a>`,
+		"iframe":             `<iframe src="https://evil.example"></iframe>`,
+		"style attribute":    `<p style="background:url(javascript:alert(1))">hi</p>`,
+		"span layout style":  `<span style="position:fixed">hi</span>`,
+		"code class abusing": "<code class=\"x\" onclick=\"alert(1)\">hi</code>",
+	}
+
+	for name, src := range xssTests {
+		t.Run(name, func(t *testing.T) {
+			out, err := Markdown(src)
+			require.NoError(t, err)
+			require.NotContains(t, out, "<script")
+			require.NotContains(t, out, "<iframe")
+			require.NotContains(t, out, "javascript:")
+			require.NotContains(t, out, "onerror")
+			require.NotContains(t, out, "onclick")
+			require.NotContains(t, out, "position")
+			require.NotContains(t, out, "background:url")
+			require.NotContains(t, out, `class="x"`)
+		})
+	}
+}
+
+func TestTableOfContents(t *testing.T) {
+	src := "# Intro\n\n## Getting *started*\n\n### Installing `go`\n\n## Getting started\n\n# Next steps"
+
+	require.Equal(t, []Heading{
+		{Level: 1, Title: "Intro", ID: "intro", Children: []Heading{
+			{Level: 2, Title: "Getting started", ID: "getting-started", Children: []Heading{
+				{Level: 3, Title: "Installing go", ID: "installing-go", Children: []Heading{}},
+			}},
+			{Level: 2, Title: "Getting started", ID: "getting-started-1", Children: []Heading{}},
+		}},
+		{Level: 1, Title: "Next steps", ID: "next-steps", Children: []Heading{}},
+	}, TableOfContents(src))
+
+	require.Empty(t, TableOfContents("no headings here"))
+}
+
+func TestPlainText(t *testing.T) {
+	out := PlainText("First line\nsecond line\n\n<b>not bold</b>")
+	require.Equal(t, "<p>First line<br>\nsecond line</p>\n<p>&lt;b&gt;not bold&lt;/b&gt;</p>\n", out)
+}
+
+func TestHighlightCSS(t *testing.T) {
+	css, err := HighlightCSS()
+	require.NoError(t, err)
+	require.Contains(t, css, ".hl-chroma .hl-s {")
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const batchSize = 100
+
+type ArticlePublisher interface {
+	PublishDueArticles(ctx context.Context, limit int) ([]int, error)
+}
+
+// Scheduler periodically publishes drafts whose publish_at has passed.
+// Several replicas can run one each, the repository skips rows claimed by another replica.
+type Scheduler struct {
+	logger   *slog.Logger
+	articles ArticlePublisher
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func NewScheduler(logger *slog.Logger, articles ArticlePublisher, interval time.Duration) *Scheduler {
+	return &Scheduler{logger: logger, articles: articles, interval: interval}
+}
+
+// Start runs the scheduler in a background goroutine until ctx is cancelled or Stop is called
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+
+	go s.run(ctx)
+}
+
+// Stop signals the scheduler to exit and waits for the current run to finish
+func (s *Scheduler) Stop() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.publishDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) publishDue(ctx context.Context) {
+	for {
+		IDs, err := s.articles.PublishDueArticles(ctx, batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Error("Scheduler: " + err.Error())
+			}
+			return
+		}
+
+		if len(IDs) > 0 {
+			s.logger.Info("Scheduler: published scheduled articles", "ids", IDs)
+		}
+
+		// a full batch means there may be more due articles waiting
+		if len(IDs) < batchSize {
+			return
+		}
+	}
+}
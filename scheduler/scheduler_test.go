@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakePublisher struct {
+	mu      sync.Mutex
+	due     int
+	calls   int
+	failing bool
+}
+
+func (f *fakePublisher) PublishDueArticles(ctx context.Context, limit int) ([]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.failing {
+		return nil, errors.New("connection refused")
+	}
+
+	var IDs []int
+	for f.due > 0 && len(IDs) < limit {
+		IDs = append(IDs, f.due)
+		f.due--
+	}
+
+	return IDs, nil
+}
+
+func (f *fakePublisher) snapshot() (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.due, f.calls
+}
+
+func TestScheduler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("drains due articles in batches", func(t *testing.T) {
+		publisher := &fakePublisher{due: batchSize*2 + 5}
+		s := NewScheduler(logger, publisher, time.Hour)
+
+		s.Start(context.Background())
+		require.Eventually(t, func() bool {
+			due, _ := publisher.snapshot()
+			return due == 0
+		}, time.Second, 10*time.Millisecond)
+		s.Stop()
+
+		_, calls := publisher.snapshot()
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("runs on every tick", func(t *testing.T) {
+		publisher := &fakePublisher{}
+		s := NewScheduler(logger, publisher, 10*time.Millisecond)
+
+		s.Start(context.Background())
+		require.Eventually(t, func() bool {
+			_, calls := publisher.snapshot()
+			return calls >= 3
+		}, time.Second, 10*time.Millisecond)
+		s.Stop()
+	})
+
+	t.Run("keeps running after errors", func(t *testing.T) {
+		publisher := &fakePublisher{failing: true}
+		s := NewScheduler(logger, publisher, 10*time.Millisecond)
+
+		s.Start(context.Background())
+		require.Eventually(t, func() bool {
+			_, calls := publisher.snapshot()
+			return calls >= 2
+		}, time.Second, 10*time.Millisecond)
+		s.Stop()
+	})
+
+	t.Run("stops when the parent context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		s := NewScheduler(logger, &fakePublisher{}, time.Hour)
+
+		s.Start(ctx)
+		cancel()
+
+		select {
+		case <-s.done:
+		case <-time.After(time.Second):
+			t.Fatal("scheduler did not stop")
+		}
+	})
+}
@@ -0,0 +1,75 @@
+// Package sitemap streams sitemaps and sitemap indexes in the sitemaps.org 0.9 format.
+package sitemap
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+const (
+	ContentType = "application/xml; charset=utf-8"
+	// MaxURLs is the most URLs a single sitemap may list
+	MaxURLs = 50000
+
+	namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+// Writer writes a document one entry at a time so the whole sitemap never has to be held in memory.
+// Close must be called to finish the document.
+type Writer struct {
+	w       *bufio.Writer
+	root    string
+	element string
+	err     error
+}
+
+// NewURLSet starts a sitemap listing pages
+func NewURLSet(w io.Writer) *Writer {
+	return newWriter(w, "urlset", "url")
+}
+
+// NewIndex starts a sitemap index listing other sitemaps
+func NewIndex(w io.Writer) *Writer {
+	return newWriter(w, "sitemapindex", "sitemap")
+}
+
+func newWriter(w io.Writer, root, element string) *Writer {
+	sw := &Writer{w: bufio.NewWriter(w), root: root, element: element}
+	sw.writeString(xml.Header + "<" + root + ` xmlns="` + namespace + `">` + "\n")
+	return sw
+}
+
+// Add writes an entry for loc, lastmod is left out when it is zero
+func (sw *Writer) Add(loc string, lastmod time.Time) error {
+	sw.writeString("  <" + sw.element + "><loc>")
+	if sw.err == nil {
+		sw.err = xml.EscapeText(sw.w, []byte(loc))
+	}
+	sw.writeString("</loc>")
+
+	if !lastmod.IsZero() {
+		sw.writeString("<lastmod>" + lastmod.UTC().Format(time.RFC3339) + "</lastmod>")
+	}
+	sw.writeString("</" + sw.element + ">\n")
+
+	return sw.err
+}
+
+// Close ends the document and flushes it to the underlying writer
+func (sw *Writer) Close() error {
+	sw.writeString("</" + sw.root + ">\n")
+
+	if sw.err != nil {
+		return sw.err
+	}
+
+	return sw.w.Flush()
+}
+
+func (sw *Writer) writeString(s string) {
+	if sw.err == nil {
+		_, sw.err = sw.w.WriteString(s)
+	}
+}
@@ -0,0 +1,52 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLSet(t *testing.T) {
+	var buf bytes.Buffer
+	lastmod := time.Date(2024, 6, 23, 22, 21, 19, 0, time.FixedZone("WAT", 3600))
+
+	w := NewURLSet(&buf)
+	require.NoError(t, w.Add("https://blog.example.com/articles/q-a?x=1&y=2", lastmod))
+	require.NoError(t, w.Add("https://blog.example.com/articles/other", time.Time{}))
+	require.NoError(t, w.Close())
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+		URLs    []struct {
+			Loc     string `xml:"loc"`
+			Lastmod string `xml:"lastmod"`
+		} `xml:"url"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.URLs, 2)
+	require.Equal(t, "https://blog.example.com/articles/q-a?x=1&y=2", doc.URLs[0].Loc)
+	require.Equal(t, "2024-06-23T21:21:19Z", doc.URLs[0].Lastmod)
+	require.Empty(t, doc.URLs[1].Lastmod)
+	require.Contains(t, buf.String(), "q-a?x=1&amp;y=2")
+}
+
+func TestIndex(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := NewIndex(&buf)
+	require.NoError(t, w.Add("https://blog.example.com/api/sitemap-1.xml", time.Date(2024, 6, 23, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, w.Close())
+
+	var doc struct {
+		XMLName  xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+		Sitemaps []struct {
+			Loc string `xml:"loc"`
+		} `xml:"sitemap"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.Sitemaps, 1)
+	require.Equal(t, "https://blog.example.com/api/sitemap-1.xml", doc.Sitemaps[0].Loc)
+}
@@ -0,0 +1,45 @@
+// Package slug turns titles and names into URL friendly identifiers.
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Make lowercases s and joins its runs of letters and digits with single hyphens.
+// It returns an empty string when s has no letters or digits.
+func Make(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range strings.ToLower(s) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pendingHyphen = b.Len() > 0
+			continue
+		}
+
+		if pendingHyphen {
+			b.WriteByte('-')
+			pendingHyphen = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// Unique returns base, or base followed by the lowest numeric suffix from 2 up, that is not in taken.
+func Unique(base string, taken []string) string {
+	used := make(map[string]bool, len(taken))
+	for _, s := range taken {
+		used[s] = true
+	}
+
+	candidate := base
+	for n := 2; used[candidate]; n++ {
+		candidate = base + "-" + strconv.Itoa(n)
+	}
+
+	return candidate
+}
@@ -0,0 +1,29 @@
+package slug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMake(t *testing.T) {
+	tests := map[string]string{
+		"I love Golang":            "i-love-golang",
+		"  Go 1.22: what's new?  ": "go-1-22-what-s-new",
+		"artificial intelligence":  "artificial-intelligence",
+		"Crème brûlée":             "crème-brûlée",
+		"c++":                      "c",
+		"!!!":                      "",
+	}
+
+	for input, want := range tests {
+		require.Equal(t, want, Make(input), input)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	require.Equal(t, "go", Unique("go", nil))
+	require.Equal(t, "go", Unique("go", []string{"go-2"}))
+	require.Equal(t, "go-2", Unique("go", []string{"go"}))
+	require.Equal(t, "go-4", Unique("go", []string{"go", "go-2", "go-3"}))
+}
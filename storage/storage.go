@@ -0,0 +1,19 @@
+// Package storage keeps uploaded files in a pluggable backend.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores opaque objects under keys chosen by the caller.
+// Keys are made of letters, digits, dots, dashes and slashes.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get returns ErrNotFound when there is no object under key, the caller must close the body
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testStorage checks the behaviour every backend must share
+func testStorage(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	_, err := s.Get(ctx, "media/missing.png")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	body := "not really a png"
+	err = s.Put(ctx, "media/ab12.png", strings.NewReader(body), int64(len(body)), "image/png")
+	require.NoError(t, err)
+
+	rc, err := s.Get(ctx, "media/ab12.png")
+	require.NoError(t, err)
+	got, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	require.Equal(t, body, string(got))
+
+	require.NoError(t, s.Delete(ctx, "media/ab12.png"))
+	_, err = s.Get(ctx, "media/ab12.png")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// deleting twice is not an error
+	require.NoError(t, s.Delete(ctx, "media/ab12.png"))
+}
+
+func TestLocal(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocal(dir)
+	require.NoError(t, err)
+
+	testStorage(t, s)
+
+	t.Run("keys stay inside the directory", func(t *testing.T) {
+		require.Equal(t, dir+"/etc/passwd", s.path("../../etc/passwd"))
+	})
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RenderConditional writes body with a strong ETag derived from it and, unless lastModified is zero,
+// a Last-Modified header. Clients that already hold this body, according to their If-None-Match or
+// If-Modified-Since headers, get a 304 Not Modified without it.
+func RenderConditional(w http.ResponseWriter, r *http.Request, contentType string, body []byte, lastModified time.Time) {
+	renderWithETag(w, r, contentType, body, `"`+hashBody(body)+`"`, lastModified)
+}
+
+// RenderConditionalResponse encodes res as JSON and writes it like RenderConditional
+func RenderConditionalResponse(w http.ResponseWriter, r *http.Request, res interface{}, lastModified time.Time) {
+	body, err := json.Marshal(res)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	RenderConditional(w, r, "application/json", append(body, '\n'), lastModified)
+}
+
+// RenderVersionedResponse is RenderConditionalResponse for resources with a version, such as "v5". The ETag
+// joins the version to a hash of the body, so it changes with anything in the body while the version can
+// still be read back from it by MatchesIfMatch.
+func RenderVersionedResponse(w http.ResponseWriter, r *http.Request, res interface{}, version string, lastModified time.Time) {
+	body, err := json.Marshal(res)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	body = append(body, '\n')
+	renderWithETag(w, r, "application/json", body, `"`+version+"-"+hashBody(body)+`"`, lastModified)
+}
+
+func hashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:16])
+}
+
+func renderWithETag(w http.ResponseWriter, r *http.Request, contentType string, body []byte, etag string, lastModified time.Time) {
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if NotModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+// MatchesIfMatch reports whether the If-Match header of r is * or lists an etag of version, either the bare
+// "v5" or one sent by RenderVersionedResponse. Weak etags never match, as RFC 9110 requires for If-Match.
+// The header must be present.
+func MatchesIfMatch(r *http.Request, version string) bool {
+	for _, candidate := range strings.Split(r.Header.Get("If-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == `"`+version+`"` || strings.HasPrefix(candidate, `"`+version+"-") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NotModified evaluates If-None-Match, or If-Modified-Since when there is none, as RFC 9110 describes for GET and HEAD
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+
+	// Last-Modified only has second precision
+	return !lastModified.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderConditional(t *testing.T) {
+	body := []byte("<rss></rss>")
+	lastModified := time.Date(2024, 6, 23, 21, 0, 0, 500, time.UTC)
+
+	render := func(headers map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/feed.rss", nil)
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+
+		w := httptest.NewRecorder()
+		RenderConditional(w, r, "application/rss+xml", body, lastModified)
+		return w
+	}
+
+	first := render(nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	require.Equal(t, "application/rss+xml", first.Header().Get("Content-Type"))
+	require.Equal(t, "Sun, 23 Jun 2024 21:00:00 GMT", first.Header().Get("Last-Modified"))
+	require.Equal(t, body, first.Body.Bytes())
+
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+	}{
+		{"matching etag", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"etag in a list", map[string]string{"If-None-Match": `"other", ` + etag}, http.StatusNotModified},
+		{"weak etag", map[string]string{"If-None-Match": "W/" + etag}, http.StatusNotModified},
+		{"stale etag", map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		{"not modified since", map[string]string{"If-Modified-Since": "Sun, 23 Jun 2024 21:00:00 GMT"}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": "Sun, 23 Jun 2024 20:59:59 GMT"}, http.StatusOK},
+		{"etag wins over date", map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": "Sun, 23 Jun 2024 21:00:00 GMT"}, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := render(tc.headers)
+			require.Equal(t, tc.status, w.Code)
+			require.Equal(t, etag, w.Header().Get("ETag"))
+
+			if tc.status == http.StatusNotModified {
+				require.Empty(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestRenderConditionalResponse(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	w := httptest.NewRecorder()
+	RenderConditionalResponse(w, r, map[string]string{"status": "success"}, time.Time{})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	require.Empty(t, w.Header().Get("Last-Modified"))
+	require.JSONEq(t, `{"status": "success"}`, w.Body.String())
+
+	r.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	RenderConditionalResponse(w, r, map[string]string{"status": "success"}, time.Time{})
+	require.Equal(t, http.StatusNotModified, w.Code)
+}
+
+func TestRenderVersionedResponse(t *testing.T) {
+	render := func(res interface{}, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		w := httptest.NewRecorder()
+		RenderVersionedResponse(w, r, res, "v2", time.Time{})
+		return w
+	}
+
+	first := render(map[string]string{"author_name": "Ayo"}, "")
+	require.Equal(t, http.StatusOK, first.Code)
+
+	etag := first.Header().Get("ETag")
+	require.True(t, strings.HasPrefix(etag, `"v2-`))
+	require.Equal(t, http.StatusNotModified, render(map[string]string{"author_name": "Ayo"}, etag).Code)
+
+	// the body changed without a new version
+	renamed := render(map[string]string{"author_name": "Ayomide"}, etag)
+	require.Equal(t, http.StatusOK, renamed.Code)
+	require.NotEqual(t, etag, renamed.Header().Get("ETag"))
+}
+
+func TestMatchesIfMatch(t *testing.T) {
+	tests := []struct {
+		ifMatch string
+		matches bool
+	}{
+		{`"v2"`, true},
+		{`"v1", "v2"`, true},
+		{`*`, true},
+		{`"v2-6f1ed002ab5595859014ebf0951522d9"`, true},
+		{`"v1"`, false},
+		{`"v20-6f1ed002ab5595859014ebf0951522d9"`, false},
+		{`W/"v2"`, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.ifMatch, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/articles/1", nil)
+			r.Header.Set("If-Match", tc.ifMatch)
+			require.Equal(t, tc.matches, MatchesIfMatch(r, "v2"))
+		})
+	}
+}